@@ -0,0 +1,271 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// bookmarksFile Is the location of bookmarks file, relative to XDG data directory.
+// Each line holds a bookmark name followed by a space and services directories,
+// which can contain spaces themselves.
+const bookmarksFile = "svctl/bookmarks"
+
+// historyDir Is the location of history files, one per services directories,
+// relative to XDG data directory.
+// legacyHistoryFile holds history from before it was kept per directory.
+const (
+	historyDir        = "svctl/hist.d"
+	legacyHistoryFile = "svctl/hist"
+)
+
+// loadBookmarks Reads bookmarks file, if there is one.
+func (c *ctl) loadBookmarks() {
+	fn, err := xdg.SearchDataFile(bookmarksFile)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		log.Printf("error reading bookmarks file: %s\n", err)
+		return
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" || line[0] == '#' {
+			continue
+		}
+		name, dir, ok := strings.Cut(line, " ")
+		if dir = strings.TrimSpace(dir); !ok || dir == "" {
+			log.Printf("error reading bookmarks file: line %d: missing directory\n", i+1)
+			continue
+		}
+		c.set("bookmarks."+name, fn, func() { c.bookmarks[name] = dir })
+	}
+}
+
+// saveBookmarks Writes all bookmarks to bookmarks file.
+func (c *ctl) saveBookmarks() error {
+	fn, err := xdg.DataFile(bookmarksFile)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(c.bookmarks))
+	for name := range c.bookmarks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s %s\n", name, c.bookmarks[name])
+		c.sources["bookmarks."+name] = fn
+	}
+	return os.WriteFile(fn, []byte(b.String()), 0644)
+}
+
+// Bookmark Remembers services directories dir under name, see Cd.
+// Relative directories are resolved against the current services directory.
+func (c *ctl) Bookmark(name, dir string) error {
+	if name == "" || strings.ContainsAny(name, "/:= \t") {
+		return fmt.Errorf("%s: invalid bookmark name", name)
+	}
+	roots, err := c.resolveRoots(dir)
+	if err != nil {
+		return err
+	}
+	c.bookmarks[name] = rootsString(roots)
+	return c.saveBookmarks()
+}
+
+// Unbookmark Forgets bookmark name.
+func (c *ctl) Unbookmark(name string) error {
+	if _, ok := c.bookmarks[name]; !ok {
+		return fmt.Errorf("%s: unable to find bookmark", name)
+	}
+	delete(c.bookmarks, name)
+	delete(c.sources, "bookmarks."+name)
+	return c.saveBookmarks()
+}
+
+// resolveRoots Parses services directories in dir, see parseRoots,
+// resolving relative ones against the current services directory.
+// All of them have to exist.
+func (c *ctl) resolveRoots(dir string) ([]svRoot, error) {
+	roots, err := parseRoots(dir)
+	if err != nil {
+		return nil, err
+	}
+	for i, root := range roots {
+		if !path.IsAbs(root.dir) {
+			roots[i].dir = path.Join(c.basedir, root.dir)
+		}
+		if fi, err := os.Stat(roots[i].dir); err != nil || !fi.IsDir() {
+			return nil, fmt.Errorf("%s: unable to find directory", roots[i].dir)
+		}
+	}
+	return roots, nil
+}
+
+// rootsString Returns services directories in the form accepted by $SVDIR.
+// A single directory is not labeled.
+func rootsString(roots []svRoot) string {
+	if len(roots) == 1 {
+		return roots[0].dir
+	}
+	dirs := make([]string, len(roots))
+	for i, root := range roots {
+		dirs[i] = fmt.Sprintf("%s=%s", root.label, root.dir)
+	}
+	return strings.Join(dirs, ":")
+}
+
+// Cd Changes services directories to dir, which is either a bookmark name
+// or directories in the form accepted by $SVDIR.
+// Without dir, changes back to the directories svctl was started with.
+//
+// History is kept separately for each services directories,
+// so it is switched as well. Selection is cleared.
+func (c *ctl) Cd(dir string) error {
+	roots := c.startRoots
+	if len(roots) == 0 {
+		roots = c.Roots()
+	}
+	if bookmark, ok := c.bookmarks[dir]; ok {
+		dir = bookmark
+	}
+	if dir != "" {
+		var err error
+		if roots, err = c.resolveRoots(dir); err != nil {
+			return err
+		}
+	}
+	c.writeHistory()
+	c.setRoots(roots)
+	c.selection = nil
+	c.readHistory(false)
+	return nil
+}
+
+// historyFile Returns location of history file for the current services directories.
+func (c *ctl) historyFile() (string, error) {
+	return xdg.DataFile(path.Join(historyDir, url.PathEscape(rootsString(c.Roots()))))
+}
+
+// readHistory Replaces history with one kept for the current services directories.
+// With legacy, history from before it was kept per directory is used,
+// if there is none yet.
+func (c *ctl) readHistory(legacy bool) {
+	if c.line == nil {
+		return
+	}
+	c.line.ClearHistory()
+	fn, err := c.historyFile()
+	if err != nil {
+		return
+	}
+	f, err := os.Open(fn)
+	if os.IsNotExist(err) && legacy {
+		fn, _ = xdg.DataFile(legacyHistoryFile)
+		f, err = os.Open(fn)
+	}
+	if err != nil {
+		return
+	}
+	c.line.ReadHistory(f)
+	f.Close()
+}
+
+// writeHistory Saves history of the current services directories to file.
+func (c *ctl) writeHistory() {
+	if c.line == nil {
+		return
+	}
+	fn, err := c.historyFile()
+	if err != nil {
+		log.Printf("error opening history file: %s\n", err)
+		return
+	}
+	f, err := os.Create(fn)
+	if err != nil {
+		log.Printf("error opening history file: %s\n", err)
+		return
+	}
+	if n, err := c.line.WriteHistory(f); err != nil {
+		log.Printf("error writing history file: %s, lines written: %d\n", err, n)
+	}
+	f.Close()
+}
+
+// Prompt Returns prompt with `{svdir}` replaced by the current services directories,
+// i.e. their labels, or the directory itself, with home shortened to '~', if there is one.
+func (c *ctl) Prompt() string {
+	svdir := c.basedir
+	if len(c.roots) > 0 {
+		labels := make([]string, len(c.roots))
+		for i, root := range c.roots {
+			labels[i] = root.label
+		}
+		svdir = strings.Join(labels, ",")
+	} else if home, err := os.UserHomeDir(); err == nil && home != "/" {
+		if svdir == home {
+			svdir = "~"
+		} else if strings.HasPrefix(svdir, home+"/") {
+			svdir = "~" + svdir[len(home):]
+		}
+	}
+	return strings.ReplaceAll(c.prompt, "{svdir}", svdir)
+}
+
+// cdCompletions Returns bookmarks and directories below the current
+// services directory, starting with prefix.
+func (c *ctl) cdCompletions(prefix string) []string {
+	compl := []string{}
+	for name := range c.bookmarks {
+		if strings.HasPrefix(name, prefix) {
+			compl = append(compl, fmt.Sprintf("%s ", name))
+		}
+	}
+	pattern := prefix + "*"
+	if !path.IsAbs(prefix) {
+		pattern = path.Join(c.basedir, pattern)
+	}
+	dirs, _ := filepath.Glob(pattern)
+	for _, dir := range dirs {
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			continue
+		}
+		if !path.IsAbs(prefix) {
+			dir, _ = filepath.Rel(c.basedir, dir)
+		}
+		compl = append(compl, dir+"/")
+	}
+	sort.Strings(compl)
+	return compl
+}
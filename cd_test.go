@@ -0,0 +1,96 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func TestCd(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	for _, service := range []string{"sys/nginx", "container/service/db"} {
+		fatal(os.MkdirAll(path.Join(dir, service, "supervise"), 0755))
+	}
+	t.Setenv("XDG_DATA_HOME", path.Join(dir, "data"))
+	xdg.Reload()
+	defer xdg.Reload()
+
+	stdout := &stdout{}
+	svctl := &ctl{stdout: stdout, basedir: path.Join(dir, "sys"), prompt: "svctl {svdir}> ", bookmarks: map[string]string{}, sources: map[string]string{}}
+	svctl.startRoots = svctl.Roots()
+
+	svctl.Ctl("cd ../container/service")
+	if svctl.basedir != path.Join(dir, "container", "service") || !equal(svctl.ServicesOf([]string{"*"}, false, false), []string{path.Join(dir, "container", "service", "db")}) {
+		t.Errorf("ERROR IN CD: `%s`", svctl.basedir)
+	}
+	if prompt := svctl.Prompt(); prompt != "svctl "+path.Join(dir, "container", "service")+"> " {
+		t.Errorf("ERROR IN PROMPT: `%s`", prompt)
+	}
+	svctl.Ctl("bookmark add box")
+	svctl.Ctl("cd")
+	if svctl.basedir != path.Join(dir, "sys") {
+		t.Errorf("ERROR IN CD: `%s` is not the initial directory", svctl.basedir)
+	}
+	svctl.Ctl("cd box")
+	if svctl.basedir != path.Join(dir, "container", "service") {
+		t.Errorf("ERROR IN CD: `%s` is not the bookmark", svctl.basedir)
+	}
+	svctl.Ctl("cd sys=" + path.Join(dir, "sys") + ":box=.")
+	svctl.Ctl("pwd")
+	expected := []string{"sys=" + path.Join(dir, "sys"), "box=" + path.Join(dir, "container", "service")}
+	if !equal(stdout.value, expected) {
+		t.Errorf("ERROR IN PWD: `%v` != `%v`", stdout.value, expected)
+	}
+	if prompt := svctl.Prompt(); prompt != "svctl sys,box> " {
+		t.Errorf("ERROR IN PROMPT: `%s`", prompt)
+	}
+	stdout.Clear()
+
+	svctl.Ctl("cd nope")
+	if !equal(stdout.value, []string{path.Join(dir, "sys", "nope") + ": unable to find directory"}) {
+		t.Errorf("ERROR IN CD: `%v`", stdout.value)
+	}
+	stdout.Clear()
+
+	spaced := path.Join(dir, "my box", "service")
+	fatal(os.MkdirAll(spaced, 0755))
+	if err := svctl.Bookmark("spaced", spaced); err != nil {
+		t.Errorf("ERROR IN BOOKMARK: %v", err)
+	}
+	loaded := &ctl{bookmarks: map[string]string{}, sources: map[string]string{}}
+	loaded.loadBookmarks()
+	if loaded.bookmarks["box"] != path.Join(dir, "container", "service") || loaded.bookmarks["spaced"] != spaced {
+		t.Errorf("ERROR IN BOOKMARKS: `%v`", loaded.bookmarks)
+	}
+	svctl.Ctl("bookmark rm box")
+	svctl.Ctl("bookmark rm spaced")
+	svctl.Ctl("bookmark")
+	if len(svctl.bookmarks) != 0 || stdout.Len() != 0 {
+		t.Errorf("ERROR IN BOOKMARKS: `%v` `%v`", svctl.bookmarks, stdout.value)
+	}
+}
@@ -23,7 +23,9 @@ package main
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // contains Checks whether str is in slice.
@@ -36,6 +38,170 @@ func contains(slice []string, str string) bool {
 	return false
 }
 
+// cmdSplit Splits command string into separate parameters.
+// Parameters are separated by spaces, unless enclosed in single or double quotes.
+func cmdSplit(cmdStr string) []string {
+	params := []string{}
+	var param strings.Builder
+	var quote rune
+	inParam := false
+	for _, r := range cmdStr {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			param.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inParam = true
+		case r == ' ' || r == '\t':
+			if inParam {
+				params = append(params, param.String())
+				param.Reset()
+				inParam = false
+			}
+		default:
+			param.WriteRune(r)
+			inParam = true
+		}
+	}
+	if inParam || len(params) == 0 {
+		params = append(params, param.String())
+	}
+	return params
+}
+
+// cmdJoin Joins parameters into a single command string,
+// quoting ones that would not survive cmdSplit otherwise.
+func cmdJoin(params []string) string {
+	quoted := make([]string, len(params))
+	for i, param := range params {
+		switch {
+		case param == "" || strings.ContainsAny(param, " \t\""):
+			quoted[i] = fmt.Sprintf("'%s'", param)
+		case strings.ContainsRune(param, '\''):
+			quoted[i] = fmt.Sprintf("\"%s\"", param)
+		default:
+			quoted[i] = param
+		}
+	}
+	return strings.Join(quoted, " ")
+}
+
+// cmdOption Describes an option accepted by a command.
+type cmdOption struct {
+	name  string
+	value string // Placeholder for option's value, empty for switches.
+	help  string
+}
+
+// cmdOptioner Defines methods for meta-commands that accept options.
+// Commands sent to runsv all accept cmdOptions.
+type cmdOptioner interface {
+	Options() []cmdOption
+}
+
+// cmdLogOptions Are options selecting log services, see findServices.
+var cmdLogOptions = []cmdOption{
+	{"--with-log", "", "Acts on log services of NAMES as well."},
+	{"--log-only", "", "Acts on log services of NAMES instead."},
+}
+
+// cmdOptions Are options accepted by all commands sent to runsv.
+var cmdOptions = append([]cmdOption{
+	{"-w", "SECONDS", "Waits up to SECONDS for the desired state (7 by default, or $SVWAIT)."},
+	{"--no-wait", "", "Does not wait for the desired state at all."},
+	{"-v", "", "Reports actions as they are sent."},
+	{"--parallel", "N", "Acts on at most N services at once."},
+}, cmdLogOptions...)
+
+// cmdParse Separates options from the rest of params.
+//
+// Options can appear anywhere, values are given either as the next param,
+// or after '='. Everything after "--" is not treated as an option.
+// Switches are present in the resulting map with empty value.
+func cmdParse(params []string, options []cmdOption) (map[string]string, []string, error) {
+	opts := map[string]string{}
+	rest := []string{}
+	for i := 0; i < len(params); i++ {
+		param := params[i]
+		if param == "" {
+			continue
+		}
+		if param == "--" {
+			rest = append(rest, params[i+1:]...)
+			break
+		}
+		if param[0] != '-' || len(param) == 1 {
+			rest = append(rest, param)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(param, "=")
+		var option *cmdOption
+		for j := range options {
+			if options[j].name == name {
+				option = &options[j]
+				break
+			}
+		}
+		if option == nil {
+			return nil, nil, fmt.Errorf("%s: unknown option", name)
+		}
+		if option.value == "" {
+			if hasValue {
+				return nil, nil, fmt.Errorf("%s: unexpected value", name)
+			}
+		} else if !hasValue {
+			if i+1 == len(params) {
+				return nil, nil, fmt.Errorf("%s: missing value", name)
+			}
+			i++
+			value = params[i]
+		}
+		opts[name] = value
+	}
+	return opts, rest, nil
+}
+
+// cmdSeconds Parses (possibly fractional) number of seconds.
+func cmdSeconds(s string) (time.Duration, error) {
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil || secs < 0 {
+		return 0, fmt.Errorf("%s: invalid number of seconds", s)
+	}
+	return time.Duration(secs * float64(time.Second)), nil
+}
+
+// cmdWait Parses number of seconds to wait for something, which has to be positive.
+func cmdWait(s string) (time.Duration, error) {
+	d, err := cmdSeconds(s)
+	if err == nil && d == 0 {
+		err = fmt.Errorf("%s: invalid number of seconds", s)
+	}
+	return d, err
+}
+
+// cmdOptionsHelp Returns help message about options.
+func cmdOptionsHelp(options []cmdOption) string {
+	usages := make([]string, len(options))
+	width := 0
+	for i, option := range options {
+		usages[i] = option.name
+		if option.value != "" {
+			usages[i] = fmt.Sprintf("%s %s", option.name, option.value)
+		}
+		if len(usages[i]) > width {
+			width = len(usages[i])
+		}
+	}
+	lines := make([]string, len(options))
+	for i, option := range options {
+		lines[i] = fmt.Sprintf("%-[1]*s%s", width+3, usages[i], option.help)
+	}
+	return strings.Join(lines, "\n")
+}
+
 // cmd Defines methods common for all commads (aka. actions)
 // available to svctl user through input prompt.
 type cmd interface {
@@ -54,6 +220,12 @@ type cmdMatcher interface {
 	Match(name string) bool
 }
 
+// cmdCompleter Defines methods for commands that need custom completion of arguments.
+// By default service names are completed.
+type cmdCompleter interface {
+	Complete(ctl *ctl, prefix string) []string
+}
+
 // cmdAll Returns all available commands.
 func cmdAll() []cmd {
 	return []cmd{
@@ -64,6 +236,26 @@ func cmdAll() []cmd {
 		&cmdSignal{},
 
 		&ctlCmdStatus{},
+		&ctlCmdTop{},
+		&ctlCmdLog{},
+		&ctlCmdLogs{},
+		&ctlCmdList{},
+		&ctlCmdEnable{},
+		&ctlCmdDisable{},
+		&ctlCmdRollingRestart{},
+		&ctlCmdForce{"force-stop", "d"},
+		&ctlCmdForce{"force-restart", "tcu"},
+		&ctlCmdForce{"force-reload", "tc"},
+		&ctlCmdSignal{},
+		&ctlCmdRotate{},
+		&ctlCmdTree{},
+		&ctlCmdDeps{},
+		&ctlCmdConfig{},
+		&ctlCmdCd{},
+		&ctlCmdPwd{},
+		&ctlCmdBookmark{},
+		&ctlCmdSelect{},
+		&ctlCmdSelection{},
 		&ctlCmdHelp{},
 		&ctlCmdExit{},
 	}
@@ -74,7 +266,8 @@ func cmdAll() []cmd {
 func cmdMatch(name string) cmd {
 	for _, cmd := range cmdAll() {
 		m, ok := cmd.(cmdMatcher)
-		if (ok && m.Match(name)) || contains(cmd.Names(), name) || string(cmd.Action()) == name {
+		action := cmd.Action()
+		if (ok && m.Match(name)) || contains(cmd.Names(), name) || (len(action) != 0 && string(action) == name) {
 			return cmd
 		}
 	}
@@ -93,3 +286,25 @@ func cmdMatchName(prefix string) []string {
 	}
 	return res
 }
+
+// cmdOptionsOf Returns options accepted by cmd.
+func cmdOptionsOf(cmd cmd) []cmdOption {
+	if optioner, ok := cmd.(cmdOptioner); ok {
+		return optioner.Options()
+	}
+	if _, ok := cmd.(ctlCmd); !ok && cmd != nil {
+		return cmdOptions
+	}
+	return nil
+}
+
+// cmdMatchOption Searches for names of cmd's options starting with `prefix`.
+func cmdMatchOption(cmd cmd, prefix string) []string {
+	res := []string{}
+	for _, option := range cmdOptionsOf(cmd) {
+		if strings.HasPrefix(option.name, prefix) {
+			res = append(res, fmt.Sprintf("%s ", option.name))
+		}
+	}
+	return res
+}
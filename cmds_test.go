@@ -0,0 +1,140 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCmdSplit(t *testing.T) {
+	defs := []struct {
+		cmd    string
+		params []string
+	}{
+		{"", []string{""}},
+		{"u", []string{"u"}},
+		{"s r0  o ", []string{"s", "r0", "o"}},
+		{"s --format='{{.Name}} {{.State}}' r*", []string{"s", "--format={{.Name}} {{.State}}", "r*"}},
+		{`s --format "{{.Name}}"`, []string{"s", "--format", "{{.Name}}"}},
+	}
+
+	for _, def := range defs {
+		params := cmdSplit(def.cmd)
+		if !equal(params, def.params) {
+			t.Errorf("ERROR IN PARAMS: `%q` != `%q` for `%s`", params, def.params, def.cmd)
+		}
+		if joined := cmdSplit(cmdJoin(params)); !equal(joined, params) {
+			t.Errorf("ERROR IN JOIN: `%q` != `%q` for `%s`", joined, params, def.cmd)
+		}
+	}
+}
+
+func TestCmdParse(t *testing.T) {
+	defs := []struct {
+		params []string
+		opts   map[string]string
+		rest   []string
+		err    string
+	}{
+		{[]string{}, map[string]string{}, []string{}, ""},
+		{[]string{"r0", "", "-v", "r1"}, map[string]string{"-v": ""}, []string{"r0", "r1"}, ""},
+		{[]string{"-w", "3", "r*", "--no-wait"}, map[string]string{"-w": "3", "--no-wait": ""}, []string{"r*"}, ""},
+		{[]string{"-w=3", "--", "-v"}, map[string]string{"-w": "3"}, []string{"-v"}, ""},
+		{[]string{"-x"}, nil, nil, "-x: unknown option"},
+		{[]string{"r0", "-w"}, nil, nil, "-w: missing value"},
+		{[]string{"-v=1"}, nil, nil, "-v: unexpected value"},
+	}
+
+	for _, def := range defs {
+		opts, rest, err := cmdParse(def.params, cmdOptions)
+		if err != nil {
+			if err.Error() != def.err {
+				t.Errorf("ERROR IN ERR: `%s` != `%s` for `%v`", err, def.err, def.params)
+			}
+			continue
+		}
+		if def.err != "" {
+			t.Errorf("ERROR IN ERR: expected `%s` for `%v`", def.err, def.params)
+		}
+		if len(opts) != len(def.opts) {
+			t.Errorf("ERROR IN OPTS: `%v` != `%v` for `%v`", opts, def.opts, def.params)
+		}
+		for name, value := range def.opts {
+			if v, ok := opts[name]; !ok || v != value {
+				t.Errorf("ERROR IN OPTS: `%v` != `%v` for `%v`", opts, def.opts, def.params)
+			}
+		}
+		if !equal(rest, def.rest) {
+			t.Errorf("ERROR IN REST: `%v` != `%v` for `%v`", rest, def.rest, def.params)
+		}
+	}
+}
+
+func TestParseOpts(t *testing.T) {
+	svctl := ctl{wait: svWait}
+	opts, _, err := svctl.parseOpts([]string{"r0"})
+	if err != nil || opts.wait != svWait || opts.noWait || opts.verbose {
+		t.Errorf("ERROR IN DEFAULTS: `%+v` (%v)", opts, err)
+	}
+	svctl.wait = 30 * time.Second
+	opts, _, err = svctl.parseOpts([]string{"-v", "r0"})
+	if err != nil || opts.wait != 30*time.Second || opts.noWait || !opts.verbose {
+		t.Errorf("ERROR IN $SVWAIT: `%+v` (%v)", opts, err)
+	}
+	opts, _, err = svctl.parseOpts([]string{"-w", "1.5", "--no-wait"})
+	if err != nil || opts.wait != 1500*time.Millisecond || !opts.noWait {
+		t.Errorf("ERROR IN -w: `%+v` (%v)", opts, err)
+	}
+	if _, _, err = svctl.parseOpts([]string{"-w", "soon"}); err == nil {
+		t.Errorf("ERROR IN -w: expected error for invalid value")
+	}
+	opts, _, err = svctl.parseOpts([]string{"--parallel=2", "--batch", "3", "r0"}, cmdOption{"--batch", "N", ""})
+	if err != nil || opts.parallel != 2 || opts.extra["--batch"] != "3" {
+		t.Errorf("ERROR IN --parallel: `%+v` (%v)", opts, err)
+	}
+	if _, _, err = svctl.parseOpts([]string{"--parallel", "0"}); err == nil {
+		t.Errorf("ERROR IN --parallel: expected error for invalid value")
+	}
+}
+
+func TestEach(t *testing.T) {
+	var mu sync.Mutex
+	running, most, called := 0, 0, 0
+	each([]string{"a", "b", "c", "d", "e"}, 2, func(service string) {
+		mu.Lock()
+		running++
+		called++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	if called != 5 || most != 2 {
+		t.Errorf("ERROR IN EACH: called %d times, %d at once", called, most)
+	}
+}
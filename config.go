@@ -0,0 +1,257 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/adrg/xdg"
+)
+
+// configFile Is the location of configuration file, relative to XDG config directories.
+const configFile = "svctl/config.toml"
+
+// config Represents contents of the configuration file.
+type config struct {
+	SVDir           string              `toml:"svdir"`
+	SVDirs          []string            `toml:"svdirs"`
+	SrcDir          string              `toml:"srcdir"`
+	Wait            float64             `toml:"wait"`
+	Prompt          string              `toml:"prompt"`
+	View            string              `toml:"view"`
+	InlineLog       bool                `toml:"inline-log"`
+	Theme           map[string]string   `toml:"theme"`
+	Aliases         map[string]string   `toml:"aliases"`
+	Groups          map[string][]string `toml:"groups"`
+	Needs           map[string][]string `toml:"needs"`
+	StopSequences   map[string]string   `toml:"stop-sequence"`
+	ReloadSequences map[string]string   `toml:"reload-sequence"`
+}
+
+// themeColors Maps color names usable in theme to their SGR codes.
+// Raw codes (e.g. "1;31") are accepted as well.
+var themeColors = map[string]string{
+	"black": "30", "red": "31", "green": "32", "yellow": "33",
+	"blue": "34", "magenta": "35", "cyan": "36", "white": "37",
+	"bold": "1", "reverse": "7",
+}
+
+// themeKeys Are the things that can be colored, i.e. lowercase states,
+// "changed" for rows highlighted by top and "loglost" for running services
+// with log service down, when shown inline.
+var themeKeys = []string{
+	"running", "starting", "stopped", "paused", "finishing", "unknown", "error", "changed", "loglost",
+}
+
+// themeCode Returns SGR code for color name or raw code.
+func themeCode(color string) (string, error) {
+	if code, ok := themeColors[color]; ok {
+		return code, nil
+	}
+	if color == "" || strings.Trim(color, "0123456789;") != "" {
+		return "", fmt.Errorf("%s: unknown color", color)
+	}
+	return color, nil
+}
+
+// setting Represents a single effective setting, along with where it came from.
+type setting struct {
+	name   string
+	value  string
+	source string
+}
+
+// configDefaults Sets default values of all settings.
+func (c *ctl) configDefaults() {
+	c.basedir = "/service"
+	c.srcdir = "/etc/sv"
+	c.wait = svWait
+	c.prompt = "svctl {svdir}> "
+	c.view = "status"
+	c.theme = map[string]string{"changed": themeColors["reverse"], "loglost": "1;31"}
+	c.aliases = map[string]string{}
+	c.groups = map[string][]string{}
+	c.bookmarks = map[string]string{}
+	c.deps = map[string][]string{}
+	c.sequences = map[string]map[string]string{}
+	for _, kind := range seqKinds {
+		c.sequences[kind] = map[string]string{}
+	}
+	c.sources = map[string]string{}
+}
+
+// loadConfig Reads configuration file, if there is one, and group files.
+// Values from the file override defaults, but not the environment.
+func (c *ctl) loadConfig() {
+	defer c.loadGroups()
+
+	fn, err := xdg.SearchConfigFile(configFile)
+	if err != nil {
+		return
+	}
+	var cfg config
+	meta, err := toml.DecodeFile(fn, &cfg)
+	if err != nil {
+		log.Printf("error reading config file: %s\n", err)
+		return
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		log.Printf("unknown keys in config file: %v\n", undecoded)
+	}
+
+	if meta.IsDefined("svdir") || meta.IsDefined("svdirs") {
+		if roots, err := parseRoots(append([]string{cfg.SVDir}, cfg.SVDirs...)...); err != nil {
+			log.Printf("error reading config file: svdir: %s\n", err)
+		} else {
+			c.set("svdir", fn, func() { c.setRoots(roots) })
+		}
+	}
+	if meta.IsDefined("srcdir") {
+		c.set("srcdir", fn, func() { c.srcdir = cfg.SrcDir })
+	}
+	if meta.IsDefined("wait") {
+		if cfg.Wait <= 0 {
+			log.Printf("error reading config file: wait: invalid number of seconds\n")
+		} else {
+			c.set("wait", fn, func() { c.wait = time.Duration(cfg.Wait * float64(time.Second)) })
+		}
+	}
+	if meta.IsDefined("prompt") {
+		c.set("prompt", fn, func() { c.prompt = cfg.Prompt })
+	}
+	if meta.IsDefined("view") {
+		c.set("view", fn, func() { c.view = cfg.View })
+	}
+	if meta.IsDefined("inline-log") {
+		c.set("inline-log", fn, func() { c.inlineLog = cfg.InlineLog })
+	}
+	for key, color := range cfg.Theme {
+		code, err := themeCode(color)
+		if err == nil && !contains(themeKeys, key) {
+			err = fmt.Errorf("%s: unknown theme key", key)
+		}
+		if err != nil {
+			log.Printf("error reading config file: %s\n", err)
+			continue
+		}
+		c.set("theme."+key, fn, func() { c.theme[key] = code })
+	}
+	for name, alias := range cfg.Aliases {
+		c.set("aliases."+name, fn, func() { c.aliases[name] = alias })
+	}
+	for name, patterns := range cfg.Groups {
+		c.set("groups."+name, fn, func() { c.groups[name] = patterns })
+	}
+	for name, patterns := range cfg.Needs {
+		c.set("needs."+name, fn, func() { c.deps[name] = patterns })
+	}
+	for name, sequence := range cfg.StopSequences {
+		c.set("stop-sequence."+name, fn, func() { c.sequences["stop-sequence"][name] = sequence })
+	}
+	for name, sequence := range cfg.ReloadSequences {
+		c.set("reload-sequence."+name, fn, func() { c.sequences["reload-sequence"][name] = sequence })
+	}
+}
+
+// loadEnv Reads settings from environment, i.e. $SVDIR and $SVWAIT.
+func (c *ctl) loadEnv() {
+	if svdir := os.Getenv("SVDIR"); svdir != "" {
+		if roots, err := parseRoots(svdir); err != nil {
+			log.Printf("error reading $SVDIR: %s\n", err)
+		} else {
+			c.set("svdir", "$SVDIR", func() { c.setRoots(roots) })
+		}
+	}
+	if wait := os.Getenv("SVWAIT"); wait != "" {
+		if d, err := cmdWait(wait); err != nil {
+			log.Printf("error reading $SVWAIT: %s\n", err)
+		} else {
+			c.set("wait", "$SVWAIT", func() { c.wait = d })
+		}
+	}
+}
+
+// set Applies setting and records where it came from.
+func (c *ctl) set(name, source string, apply func()) {
+	apply()
+	c.sources[name] = source
+}
+
+// source Returns where setting came from.
+func (c *ctl) source(name string) string {
+	if source, ok := c.sources[name]; ok {
+		return source
+	}
+	return "default"
+}
+
+// Settings Returns all effective settings.
+func (c *ctl) Settings() []setting {
+	settings := []setting{
+		{"svdir", rootsString(c.Roots()), c.source("svdir")},
+		{"srcdir", c.srcdir, c.source("srcdir")},
+		{"wait", c.wait.String(), c.source("wait")},
+		{"prompt", fmt.Sprintf("%q", c.prompt), c.source("prompt")},
+		{"view", fmt.Sprintf("%q", c.view), c.source("view")},
+		{"inline-log", strconv.FormatBool(c.inlineLog), c.source("inline-log")},
+	}
+	more := []setting{}
+	for key, code := range c.theme {
+		more = append(more, setting{"theme." + key, code, c.source("theme." + key)})
+	}
+	for name, alias := range c.aliases {
+		more = append(more, setting{"aliases." + name, alias, c.source("aliases." + name)})
+	}
+	for name, patterns := range c.groups {
+		more = append(more, setting{"groups." + name, strings.Join(patterns, " "), c.source("groups." + name)})
+	}
+	for name, dir := range c.bookmarks {
+		more = append(more, setting{"bookmarks." + name, dir, c.source("bookmarks." + name)})
+	}
+	for name, patterns := range c.deps {
+		more = append(more, setting{"needs." + name, strings.Join(patterns, " "), c.source("needs." + name)})
+	}
+	for kind, sequences := range c.sequences {
+		for name, sequence := range sequences {
+			key := kind + "." + name
+			more = append(more, setting{key, sequence, c.source(key)})
+		}
+	}
+	sort.Slice(more, func(i, j int) bool { return more[i].name < more[j].name })
+	return append(settings, more...)
+}
+
+// colorize Colors line according to theme key.
+func (c *ctl) colorize(key, line string) string {
+	code, ok := c.theme[key]
+	if !ok || !c.colors {
+		return line
+	}
+	return fmt.Sprintf("\033[%sm%s\033[0m", code, line)
+}
@@ -0,0 +1,137 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+func TestConfig(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	fatal(os.MkdirAll(path.Join(dir, "svctl"), 0755))
+	fn := path.Join(dir, "svctl", "config.toml")
+	fatal(os.WriteFile(fn, []byte(`
+svdir = "/var/service"
+wait = 30
+prompt = "> "
+view = ""
+inline-log = true
+
+[theme]
+running = "green"
+stopped = "1;31"
+bogus = "red"
+error = "nope"
+
+[aliases]
+rr = "restart -w 60"
+
+[groups]
+frontend = ["nginx", "api-*"]
+
+[stop-sequence]
+db = "INT"
+`), 0644))
+
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+	t.Setenv("SVDIR", "/etc/service")
+	t.Setenv("SVWAIT", "")
+	xdg.Reload()
+	defer xdg.Reload()
+
+	svctl := newCtl(&stdout{}, false)
+	expected := []setting{
+		{"svdir", "/etc/service", "$SVDIR"},
+		{"srcdir", "/etc/sv", "default"},
+		{"wait", (30 * time.Second).String(), fn},
+		{"prompt", `"> "`, fn},
+		{"view", `""`, fn},
+		{"inline-log", "true", fn},
+		{"aliases.rr", "restart -w 60", fn},
+		{"groups.frontend", "nginx api-*", fn},
+		{"stop-sequence.db", "INT", fn},
+		{"theme.changed", "7", "default"},
+		{"theme.loglost", "1;31", "default"},
+		{"theme.running", "32", fn},
+		{"theme.stopped", "1;31", fn},
+	}
+	settings := svctl.Settings()
+	if len(settings) != len(expected) {
+		t.Errorf("ERROR IN SETTINGS: `%v` != `%v`", settings, expected)
+	}
+	for i := range expected {
+		if i < len(settings) && settings[i] != expected[i] {
+			t.Errorf("ERROR IN SETTING: `%v` != `%v`", settings[i], expected[i])
+		}
+	}
+
+	svctl.colors = true
+	if line := svctl.colorize("running", "r0"); line != "\033[32mr0\033[0m" {
+		t.Errorf("ERROR IN COLORIZE: `%q`", line)
+	}
+	if line := svctl.colorize("paused", "r0"); line != "r0" {
+		t.Errorf("ERROR IN COLORIZE: `%q`", line)
+	}
+}
+
+func TestConfigWait(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_DIRS", t.TempDir())
+	t.Setenv("SVWAIT", "0")
+	xdg.Reload()
+	defer xdg.Reload()
+
+	svctl := newCtl(&stdout{}, false)
+	if svctl.wait != svWait || svctl.source("wait") != "default" {
+		t.Errorf("ERROR IN WAIT: `%s` from `%s` for SVWAIT=0", svctl.wait, svctl.source("wait"))
+	}
+	if _, _, err := svctl.parseOpts([]string{"-w", "0"}); err == nil {
+		t.Errorf("ERROR IN WAIT: expected error for -w 0")
+	}
+	opts, _, err := svctl.parseOpts(nil)
+	if err != nil || opts.wait != svWait {
+		t.Errorf("ERROR IN WAIT: `%+v` (%v)", opts, err)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	stdout := &stdout{}
+	svctl := ctl{stdout: stdout, aliases: map[string]string{"h": "help up", "x": "nope"}}
+
+	svctl.Ctl("h")
+	if stdout.Len() != 10 {
+		t.Errorf("ERROR IN ALIAS: `%v` is not help for up", stdout.value)
+	}
+	stdout.Clear()
+	svctl.Ctl("x")
+	if !equal(stdout.value, []string{"nope: unable to find action"}) {
+		t.Errorf("ERROR IN ALIAS: `%v`", stdout.value)
+	}
+}
@@ -21,7 +21,17 @@
 
 package main
 
-import "strings"
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
 
 // ctlCmd Defines methods common for svctl meta-commands, i.e. ones
 // that are not sent to runit, but executed locally.
@@ -39,8 +49,11 @@ func (c *ctlCmdStatus) Action() []byte {
 func (c *ctlCmdStatus) Help() string {
 	return strings.TrimSpace(`
 status [NAMES...]   Shows status(es) of service(s) with matching NAMES.
-                    When invoked without NAMES, shows statuses of all processes.
+                    When invoked without NAMES, shows statuses of selected (or all) processes.
                     NAMES support globing with '*' and '?'.
+                    --format=FORMAT prints statuses as 'json', 'csv'
+                    or using Go template, e.g. '{{.Name}} {{.State}}'.
+                    --inline-log shows log services on their parent services rows.
 	`)
 }
 
@@ -48,20 +61,892 @@ func (c *ctlCmdStatus) Names() []string {
 	return []string{"status"}
 }
 
+func (c *ctlCmdStatus) Options() []cmdOption {
+	return []cmdOption{
+		{"--format", "FORMAT", "Prints statuses as 'json', 'csv' or using Go template."},
+		{"--inline-log", "", "Shows log services on their parent services rows."},
+	}
+}
+
 func (c *ctlCmdStatus) Run(ctl *ctl, params []string) bool {
-	if len(params) == 1 {
-		ctl.Status("*", true)
+	opts, dirs, err := cmdParse(params[1:], c.Options())
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	format, ok := opts["--format"]
+	if !ok {
+		format = ctl.format
+	}
+	_, inline := opts["--inline-log"]
+	statuses := ctl.StatusesOf(dirs, true)
+	if format == "" && (inline || ctl.inlineLog) {
+		statuses = inlineLogs(statuses)
+	}
+	ctl.PrintStatuses(statuses, format)
+	return false
+}
+
+// ctlCmdTop Defines the "top" action.
+type ctlCmdTop struct{}
+
+func (c *ctlCmdTop) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdTop) Help() string {
+	return strings.TrimSpace(`
+top [-n SECONDS] [NAMES...]   Shows status(es) of service(s) with matching NAMES,
+                              refreshing every SECONDS (2 by default) until a key is pressed.
+                              Most recently (re)started services are shown first and
+                              these that changed since previous refresh are highlighted.
+                              NAMES support globing with '*' and '?'.
+	`)
+}
+
+func (c *ctlCmdTop) Names() []string {
+	return []string{"top"}
+}
+
+func (c *ctlCmdTop) Options() []cmdOption {
+	return []cmdOption{{"-n", "SECONDS", "Refreshes every SECONDS."}}
+}
+
+func (c *ctlCmdTop) Run(ctl *ctl, params []string) bool {
+	opts, ids, err := cmdParse(params[1:], c.Options())
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	interval := 2 * time.Second
+	if n, ok := opts["-n"]; ok {
+		if interval, err = cmdSeconds(n); err != nil || interval == 0 {
+			ctl.fail()
+			ctl.printf("%s: invalid interval\n", n)
+			return false
+		}
+	}
+	ctl.Top(ids, interval)
+	return false
+}
+
+// ctlCmdLog Defines the "log" action.
+type ctlCmdLog struct{}
+
+func (c *ctlCmdLog) Action() []byte {
+	return []byte{'l'}
+}
+
+func (c *ctlCmdLog) Help() string {
+	return strings.TrimSpace(`
+log NAME [-n LINES] [-f] [-d DIR]   Shows last LINES (10 by default) lines logged by service NAME.
+                                    With -f, keeps showing new lines until a key is pressed.
+                                    Log directory is read from NAME's log/run script,
+                                    unless specified with -d.
+	`)
+}
+
+func (c *ctlCmdLog) Names() []string {
+	return []string{"log"}
+}
+
+func (c *ctlCmdLog) Options() []cmdOption {
+	return []cmdOption{
+		{"-n", "LINES", "Shows last LINES lines."},
+		{"-f", "", "Keeps showing new lines."},
+		{"-d", "DIR", "Reads logs from DIR."},
+	}
+}
+
+func (c *ctlCmdLog) Run(ctl *ctl, params []string) bool {
+	opts, names, err := cmdParse(params[1:], c.Options())
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	n := 10
+	if lines, ok := opts["-n"]; ok {
+		if n, err = strconv.Atoi(lines); err != nil || n < 0 {
+			ctl.fail()
+			ctl.printf("%s: invalid number of lines\n", lines)
+			return false
+		}
+	}
+	_, follow := opts["-f"]
+	if len(names) != 1 {
+		ctl.fail()
+		ctl.println("log: exactly one NAME expected")
+		return false
+	}
+
+	services := ctl.Services(names[0], false)
+	switch len(services) {
+	case 0:
+		ctl.fail()
+		ctl.printf("%s: unable to find service\n", names[0])
+	case 1:
+		ctl.Log(services[0], opts["-d"], n, follow)
+	default:
+		ctl.fail()
+		ctl.printf("%s: matches more than one service\n", names[0])
+	}
+	return false
+}
+
+// ctlCmdLogs Defines the "logs" action.
+type ctlCmdLogs struct{}
+
+func (c *ctlCmdLogs) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdLogs) Help() string {
+	return strings.TrimSpace(`
+logs [OPTIONS] NAMES...   Shows lines logged by service(s) with matching NAMES,
+                          including rotated files, merged into a single timeline.
+                          NAMES support globing with '*' and '?'.
+                          --since TIME, --until TIME limit lines to the time range,
+                          TIME is either a duration ago (e.g. '1h') or a local time
+                          (e.g. '2006-01-02T15:04:05').
+                          --grep REGEXP limits lines to the matching ones.
+                          --json prints lines as JSON objects.
+	`)
+}
+
+func (c *ctlCmdLogs) Names() []string {
+	return []string{"logs"}
+}
+
+func (c *ctlCmdLogs) Options() []cmdOption {
+	return []cmdOption{
+		{"--since", "TIME", "Shows lines logged since TIME."},
+		{"--until", "TIME", "Shows lines logged until TIME."},
+		{"--grep", "REGEXP", "Shows lines matching REGEXP."},
+		{"--json", "", "Prints lines as JSON objects."},
+	}
+}
+
+func (c *ctlCmdLogs) Run(ctl *ctl, params []string) bool {
+	opts, names, err := cmdParse(params[1:], c.Options())
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	query := &logQuery{}
+	now := time.Now()
+	if since, ok := opts["--since"]; ok && err == nil {
+		query.since, err = logTime(since, now)
+	}
+	if until, ok := opts["--until"]; ok && err == nil {
+		query.until, err = logTime(until, now)
+	}
+	if grep, ok := opts["--grep"]; ok && err == nil {
+		query.grep, err = regexp.Compile(grep)
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	_, asJSON := opts["--json"]
+	if services := ctl.findServices(names, &ctlOpts{}); len(services) > 0 {
+		ctl.Logs(services, query, asJSON)
+	}
+	return false
+}
+
+// ctlCmdList Defines the "list" action.
+type ctlCmdList struct{}
+
+func (c *ctlCmdList) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdList) Help() string {
+	return strings.TrimSpace(`
+list [--available]   Lists enabled services.
+                     With --available, lists service definitions that can be enabled instead.
+	`)
+}
+
+func (c *ctlCmdList) Names() []string {
+	return []string{"list"}
+}
+
+func (c *ctlCmdList) Options() []cmdOption {
+	return []cmdOption{{"--available", "", "Lists service definitions that can be enabled."}}
+}
+
+func (c *ctlCmdList) Run(ctl *ctl, params []string) bool {
+	opts, rest, err := cmdParse(params[1:], c.Options())
+	if err == nil && len(rest) != 0 {
+		err = fmt.Errorf("list: unexpected arguments")
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	if _, ok := opts["--available"]; ok {
+		for _, file := range ctl.Available() {
+			ctl.println(path.Base(file))
+		}
+		return false
+	}
+	for _, service := range ctl.Services("*", false) {
+		ctl.println(ctl.serviceName(service))
+	}
+	return false
+}
+
+// ctlCmdEnable Defines the "enable" action.
+type ctlCmdEnable struct{}
+
+func (c *ctlCmdEnable) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdEnable) Help() string {
+	return strings.TrimSpace(`
+enable NAMES...   Enables service(s) with matching NAMES, by linking
+                  their definitions from source directory (/etc/sv by default).
+                  NAMES support globing with '*' and '?'.
+	`)
+}
+
+func (c *ctlCmdEnable) Names() []string {
+	return []string{"enable"}
+}
+
+func (c *ctlCmdEnable) Complete(ctl *ctl, prefix string) []string {
+	return ctl.enableCompletions(prefix)
+}
+
+func (c *ctlCmdEnable) Run(ctl *ctl, params []string) bool {
+	for _, param := range params[1:] {
+		if param == "" {
+			continue
+		}
+		ctl.Enable(param)
+	}
+	return false
+}
+
+// ctlCmdDisable Defines the "disable" action.
+type ctlCmdDisable struct{}
+
+func (c *ctlCmdDisable) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdDisable) Help() string {
+	return strings.TrimSpace(`
+disable [OPTIONS] NAMES...   Disables service(s) with matching NAMES, by stopping them,
+                             waiting for runsv to exit and removing their links.
+                             NAMES support globing with '*' and '?'.
+                             Accepts the same OPTIONS as commands sent to runsv.
+	`)
+}
+
+func (c *ctlCmdDisable) Names() []string {
+	return []string{"disable"}
+}
+
+func (c *ctlCmdDisable) Options() []cmdOption {
+	return cmdOptions
+}
+
+func (c *ctlCmdDisable) Run(ctl *ctl, params []string) bool {
+	opts, names, err := ctl.parseOpts(params[1:])
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	for _, name := range names {
+		ctl.Disable(name, opts)
+	}
+	return false
+}
+
+// ctlCmdRollingRestart Defines the "rolling-restart" action.
+type ctlCmdRollingRestart struct{}
+
+func (c *ctlCmdRollingRestart) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdRollingRestart) Help() string {
+	return strings.TrimSpace(`
+rolling-restart [OPTIONS] NAMES...   Restarts service(s) with matching NAMES in batches,
+                                     moving to the next batch only when the current one
+                                     is back up. Stops at the first batch that fails and
+                                     reports services that were left untouched.
+                                     --batch=N restarts N services at a time (1 by default).
+                                     --pause=DURATION waits between batches, e.g. '5s'.
+                                     Accepts the same OPTIONS as commands sent to runsv,
+                                     except for --no-wait.
+	`)
+}
+
+func (c *ctlCmdRollingRestart) Names() []string {
+	return []string{"rolling-restart"}
+}
+
+func (c *ctlCmdRollingRestart) Options() []cmdOption {
+	return append([]cmdOption{
+		{"--batch", "N", "Restarts N services at a time."},
+		{"--pause", "DURATION", "Waits DURATION between batches."},
+	}, cmdOptions...)
+}
+
+// parse Separates batch size and pause from the rest of params.
+func (c *ctlCmdRollingRestart) parse(ctl *ctl, params []string) (int, time.Duration, *ctlOpts, []string, error) {
+	opts, names, err := ctl.parseOpts(params[1:], c.Options()[:2]...)
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+	if opts.noWait {
+		return 0, 0, nil, nil, fmt.Errorf("%s: batches have to wait for each other, --no-wait cannot be used", params[0])
+	}
+	batch := 1
+	if value, ok := opts.extra["--batch"]; ok {
+		if batch, err = strconv.Atoi(value); err != nil || batch < 1 {
+			return 0, 0, nil, nil, fmt.Errorf("%s: invalid batch size", value)
+		}
+	}
+	var pause time.Duration
+	if value, ok := opts.extra["--pause"]; ok {
+		if pause, err = time.ParseDuration(value); err != nil {
+			return 0, 0, nil, nil, fmt.Errorf("%s: invalid duration", value)
+		}
+	}
+	return batch, pause, opts, names, nil
+}
+
+func (c *ctlCmdRollingRestart) Run(ctl *ctl, params []string) bool {
+	batch, pause, opts, names, err := c.parse(ctl, params)
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	services := ctl.findServices(names, opts)
+	if !ctl.denied(services) {
+		ctl.RollingRestart(services, batch, pause, opts)
+	}
+	return false
+}
+
+// ctlCmdForce Defines common struct for the "force-*" actions, which
+// escalate to KILL when service does not react in time.
+type ctlCmdForce struct {
+	name   string
+	action string
+}
+
+func (c *ctlCmdForce) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdForce) Help() string {
+	switch c.name {
+	case "force-stop":
+		return strings.TrimSpace(`
+force-stop [OPTIONS] NAMES...   Stops service(s) with matching NAMES, sending KILL
+                                if they are still up after the grace period.
+                                --grace=SECONDS sets the grace period (-w by default).
+                                Reports each step and how long it took.
+                                Accepts the same OPTIONS as commands sent to runsv,
+                                except for --no-wait.
+		`)
+	case "force-restart":
+		return strings.TrimSpace(`
+force-restart [OPTIONS] NAMES...   Restarts service(s) with matching NAMES, sending KILL
+                                   if they do not restart within the grace period.
+                                   Accepts the same OPTIONS as force-stop.
+		`)
+	}
+	return strings.TrimSpace(`
+force-reload [OPTIONS] NAMES...   Sends TERM and CONT to service(s) with matching NAMES,
+                                  sending KILL if they do not restart within the grace period.
+                                  Accepts the same OPTIONS as force-stop.
+	`)
+}
+
+func (c *ctlCmdForce) Names() []string {
+	return []string{c.name}
+}
+
+func (c *ctlCmdForce) Options() []cmdOption {
+	return append([]cmdOption{
+		{"--grace", "SECONDS", "Sends KILL after SECONDS."},
+	}, cmdOptions...)
+}
+
+func (c *ctlCmdForce) Run(ctl *ctl, params []string) bool {
+	opts, names, err := ctl.parseOpts(params[1:], c.Options()[0])
+	if err == nil && opts.noWait {
+		err = fmt.Errorf("%s: escalating needs waiting, --no-wait cannot be used", params[0])
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	grace := opts.wait
+	if value, ok := opts.extra["--grace"]; ok {
+		if grace, err = cmdWait(value); err != nil {
+			ctl.fail()
+			ctl.println(err)
+			return false
+		}
+	}
+	services := ctl.findServices(names, opts)
+	if !ctl.denied(services) {
+		ctl.Force([]byte(c.action), services, grace, opts)
+	}
+	return false
+}
+
+// ctlCmdSignal Defines the "signal" action.
+type ctlCmdSignal struct{}
+
+func (c *ctlCmdSignal) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdSignal) Help() string {
+	return strings.TrimSpace(`
+signal SIG [--tree] NAMES...   Sends signal SIG directly to process(es) of service(s)
+                               with matching NAMES, bypassing runsv.
+                               SIG is a name (e.g. WINCH, SIGUSR1, RTMIN+2) or number.
+                               --tree sends it to all their descendants as well.
+                               Accepts --with-log and --log-only, see OPTIONS.
+	`)
+}
+
+func (c *ctlCmdSignal) Names() []string {
+	return []string{"signal"}
+}
+
+func (c *ctlCmdSignal) Options() []cmdOption {
+	return append(
+		[]cmdOption{{"--tree", "", "Sends signal to all descendants as well."}},
+		cmdLogOptions...,
+	)
+}
+
+func (c *ctlCmdSignal) Run(ctl *ctl, params []string) bool {
+	opts, names, err := cmdParse(params[1:], c.Options())
+	if err == nil && len(names) == 0 {
+		err = fmt.Errorf("%s: missing SIG", params[0])
+	}
+	var sig syscall.Signal
+	if err == nil {
+		sig, err = sigParse(names[0])
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	_, tree := opts["--tree"]
+	_, withLog := opts["--with-log"]
+	_, logOnly := opts["--log-only"]
+	services := ctl.findServices(names[1:], &ctlOpts{withLog: withLog, logOnly: logOnly})
+	ctl.Signal(names[0], sig, services, tree)
+	return false
+}
+
+// ctlCmdRotate Defines the "rotate" action.
+type ctlCmdRotate struct{}
+
+func (c *ctlCmdRotate) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdRotate) Help() string {
+	return strings.TrimSpace(`
+rotate [OPTIONS] NAMES...   Makes log service(s) of service(s) with matching NAMES
+                            rotate their logs, by sending them ALRM.
+                            Does not wait for anything, as svlogd keeps running.
+	`)
+}
+
+func (c *ctlCmdRotate) Names() []string {
+	return []string{"rotate"}
+}
+
+func (c *ctlCmdRotate) Options() []cmdOption {
+	return []cmdOption{{"-v", "", "Reports actions as they are sent."}}
+}
+
+func (c *ctlCmdRotate) Run(ctl *ctl, params []string) bool {
+	parsed, names, err := cmdParse(params[1:], c.Options())
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	_, verbose := parsed["-v"]
+	opts := &ctlOpts{noWait: true, verbose: verbose, logOnly: true}
+	services := ctl.findServices(names, opts)
+	if !ctl.denied(services) {
+		ctl.ctlAll([]byte{'a'}, services, svNow(), opts)
+	}
+	return false
+}
+
+// ctlCmdTree Defines the "tree" action.
+type ctlCmdTree struct{}
+
+func (c *ctlCmdTree) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdTree) Help() string {
+	return strings.TrimSpace(`
+tree [DIRS...]   Shows services below DIRS (the services directory by default)
+                 as a tree, with up/down counts of each subtree.
+                 Directories watched by nested runsvdir instances are shown
+                 below their services.
+	`)
+}
+
+func (c *ctlCmdTree) Names() []string {
+	return []string{"tree"}
+}
+
+func (c *ctlCmdTree) Run(ctl *ctl, params []string) bool {
+	dirs := []string{}
+	for _, param := range params[1:] {
+		if param != "" {
+			dirs = append(dirs, param)
+		}
+	}
+	if len(dirs) == 0 {
+		for _, root := range ctl.Roots() {
+			dirs = append(dirs, root.dir)
+		}
+	}
+	for _, dir := range dirs {
+		if !path.IsAbs(dir) {
+			roots, rest := ctl.rootsOf(dir)
+			dir = path.Join(roots[0].dir, rest)
+		}
+		if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+			ctl.fail()
+			ctl.printf("%s: unable to find directory\n", ctl.serviceName(dir))
+			continue
+		}
+		ctl.PrintTree(dir)
+	}
+	return false
+}
+
+// ctlCmdDeps Defines the "deps" action.
+type ctlCmdDeps struct{}
+
+func (c *ctlCmdDeps) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdDeps) Help() string {
+	return strings.TrimSpace(`
+deps [--dot] [NAMES...]   Shows dependencies of service(s) with matching NAMES as a tree.
+                          When invoked without NAMES, shows dependencies of selected (or all) services.
+                          --dot prints them as a graph in DOT format instead.
+	`)
+}
+
+func (c *ctlCmdDeps) Names() []string {
+	return []string{"deps"}
+}
+
+func (c *ctlCmdDeps) Options() []cmdOption {
+	return []cmdOption{{"--dot", "", "Prints dependencies in DOT format."}}
+}
+
+func (c *ctlCmdDeps) Run(ctl *ctl, params []string) bool {
+	opts, names, err := cmdParse(params[1:], c.Options())
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	services := ctl.selected(names, false, true)
+
+	graph, err := ctl.depsGraph(services, false)
+	if err == nil {
+		_, err = ctl.depsLayers(graph)
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+		return false
+	}
+	if _, ok := opts["--dot"]; ok {
+		ctl.DepsDot(graph)
+		return false
+	}
+	for _, service := range services {
+		ctl.DepsTree(service, graph)
+	}
+	return false
+}
+
+// ctlCmdConfig Defines the "config" action.
+type ctlCmdConfig struct{}
+
+func (c *ctlCmdConfig) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdConfig) Help() string {
+	return strings.TrimSpace(`
+config   Shows effective settings and where they come from.
+	`)
+}
+
+func (c *ctlCmdConfig) Names() []string {
+	return []string{"config"}
+}
+
+func (c *ctlCmdConfig) Run(ctl *ctl, params []string) bool {
+	settings := ctl.Settings()
+	widths := make([]int, 2)
+	for _, setting := range settings {
+		if len(setting.name) > widths[0] {
+			widths[0] = len(setting.name)
+		}
+		if len(setting.value) > widths[1] {
+			widths[1] = len(setting.value)
+		}
+	}
+	for _, setting := range settings {
+		ctl.printf(
+			"%-[1]*s%-[3]*s%s\n",
+			widths[0]+3, setting.name, widths[1]+3, setting.value, setting.source,
+		)
+	}
+	return false
+}
+
+// ctlCmdCd Defines the "cd" action.
+type ctlCmdCd struct{}
+
+func (c *ctlCmdCd) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdCd) Help() string {
+	return strings.TrimSpace(`
+cd [DIR|BOOKMARK]   Changes services directory to DIR (relative to the current one)
+                    or to directories bookmarked as BOOKMARK.
+                    DIR can be a list of directories, like $SVDIR.
+                    When invoked without arguments, changes back to the initial one.
+                    Clears the selection.
+	`)
+}
+
+func (c *ctlCmdCd) Names() []string {
+	return []string{"cd"}
+}
+
+func (c *ctlCmdCd) Complete(ctl *ctl, prefix string) []string {
+	return ctl.cdCompletions(prefix)
+}
+
+func (c *ctlCmdCd) Run(ctl *ctl, params []string) bool {
+	dirs := []string{}
+	for _, param := range params[1:] {
+		if param != "" {
+			dirs = append(dirs, param)
+		}
+	}
+	var err error
+	if len(dirs) > 1 {
+		err = fmt.Errorf("cd: too many arguments")
 	} else {
-		for _, dir := range params[1:] {
-			if dir == "" {
-				continue
-			}
-			ctl.Status(dir, true)
+		err = ctl.Cd(strings.Join(dirs, ""))
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+	}
+	return false
+}
+
+// ctlCmdPwd Defines the "pwd" action.
+type ctlCmdPwd struct{}
+
+func (c *ctlCmdPwd) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdPwd) Help() string {
+	return strings.TrimSpace(`
+pwd   Shows the current services directory.
+	`)
+}
+
+func (c *ctlCmdPwd) Names() []string {
+	return []string{"pwd"}
+}
+
+func (c *ctlCmdPwd) Run(ctl *ctl, params []string) bool {
+	for _, root := range ctl.Roots() {
+		if root.label == "" {
+			ctl.println(root.dir)
+		} else {
+			ctl.printf("%s=%s\n", root.label, root.dir)
 		}
 	}
 	return false
 }
 
+// ctlCmdBookmark Defines the "bookmark" action.
+type ctlCmdBookmark struct{}
+
+func (c *ctlCmdBookmark) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdBookmark) Help() string {
+	return strings.TrimSpace(`
+bookmark [add NAME [DIR]|rm NAME]   Bookmarks DIR (the current services directory by default)
+                                    as NAME, to use with cd, or removes bookmark NAME.
+                                    When invoked without arguments, shows bookmarks.
+	`)
+}
+
+func (c *ctlCmdBookmark) Names() []string {
+	return []string{"bookmark"}
+}
+
+func (c *ctlCmdBookmark) Complete(ctl *ctl, prefix string) []string {
+	compl := []string{}
+	for _, sub := range []string{"add", "rm"} {
+		if strings.HasPrefix(sub, prefix) {
+			compl = append(compl, fmt.Sprintf("%s ", sub))
+		}
+	}
+	for name := range ctl.bookmarks {
+		if strings.HasPrefix(name, prefix) {
+			compl = append(compl, fmt.Sprintf("%s ", name))
+		}
+	}
+	sort.Strings(compl)
+	return compl
+}
+
+func (c *ctlCmdBookmark) Run(ctl *ctl, params []string) bool {
+	args := []string{}
+	for _, param := range params[1:] {
+		if param != "" {
+			args = append(args, param)
+		}
+	}
+	var err error
+	switch {
+	case len(args) == 0:
+		names := make([]string, 0, len(ctl.bookmarks))
+		for name := range ctl.bookmarks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ctl.printf("%s   %s\n", name, ctl.bookmarks[name])
+		}
+	case args[0] == "add" && len(args) == 2:
+		err = ctl.Bookmark(args[1], rootsString(ctl.Roots()))
+	case args[0] == "add" && len(args) == 3:
+		err = ctl.Bookmark(args[1], args[2])
+	case args[0] == "rm" && len(args) == 2:
+		err = ctl.Unbookmark(args[1])
+	default:
+		err = fmt.Errorf("bookmark: invalid arguments")
+	}
+	if err != nil {
+		ctl.fail()
+		ctl.println(err)
+	}
+	return false
+}
+
+// ctlCmdSelect Defines the "select" action.
+type ctlCmdSelect struct{}
+
+func (c *ctlCmdSelect) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdSelect) Help() string {
+	return strings.TrimSpace(`
+select [NAMES...] [+NAMES...] [-NAMES...]   Selects service(s) with matching NAMES, to act on
+                                            when commands are invoked without NAMES.
+                                            +NAMES adds to and -NAMES removes from the selection.
+                                            When invoked without arguments, clears the selection.
+	`)
+}
+
+func (c *ctlCmdSelect) Names() []string {
+	return []string{"select"}
+}
+
+func (c *ctlCmdSelect) Complete(ctl *ctl, prefix string) []string {
+	if prefix == "" || (prefix[0] != '+' && prefix[0] != '-') {
+		return ctl.nameCompletions(prefix)
+	}
+	compl := ctl.nameCompletions(prefix[1:])
+	for i, name := range compl {
+		compl[i] = prefix[:1] + name
+	}
+	return compl
+}
+
+func (c *ctlCmdSelect) Run(ctl *ctl, params []string) bool {
+	patterns := []string{}
+	for _, param := range params[1:] {
+		if param != "" {
+			patterns = append(patterns, param)
+		}
+	}
+	ctl.Select(patterns)
+	return false
+}
+
+// ctlCmdSelection Defines the "selection" action.
+type ctlCmdSelection struct{}
+
+func (c *ctlCmdSelection) Action() []byte {
+	return nil
+}
+
+func (c *ctlCmdSelection) Help() string {
+	return strings.TrimSpace(`
+selection   Shows statuses of selected services.
+	`)
+}
+
+func (c *ctlCmdSelection) Names() []string {
+	return []string{"selection"}
+}
+
+func (c *ctlCmdSelection) Run(ctl *ctl, params []string) bool {
+	if len(ctl.selection) == 0 {
+		ctl.println("no services selected")
+		return false
+	}
+	ctl.PrintStatuses(ctl.StatusesOf(nil, false), ctl.format)
+	return false
+}
+
 // ctlCmdHelp Defines the "help" action.
 // Note: Acronym is '?' here, because 'h' is taken by "hup".
 type ctlCmdHelp struct{}
@@ -82,8 +967,20 @@ func (c *ctlCmdHelp) Names() []string {
 }
 
 func (c *ctlCmdHelp) Run(ctl *ctl, params []string) bool {
+	// Options common for all commands sent to runsv are printed once, at the end.
+	options := false
+	defer func() {
+		if options {
+			ctl.println("OPTIONS:")
+			ctl.println(cmdOptionsHelp(cmdOptions))
+		}
+	}()
+
 	if len(params) == 1 {
 		for _, cmd := range cmdAll() {
+			if _, ok := cmd.(ctlCmd); !ok {
+				options = true
+			}
 			match, ok := cmd.(cmdMatcher)
 			if !ok {
 				ctl.println(cmd.Help())
@@ -100,9 +997,12 @@ func (c *ctlCmdHelp) Run(ctl *ctl, params []string) bool {
 		cmd := cmdMatch(param)
 		if cmd == nil {
 			ctl.printf("%s: unable to find action\n", param)
-		} else {
-			ctl.println(cmd.Help())
+			continue
+		}
+		if _, ok := cmd.(ctlCmd); !ok {
+			options = true
 		}
+		ctl.println(cmd.Help())
 	}
 	return false
 }
@@ -24,6 +24,7 @@ package main
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/peterh/liner"
 )
@@ -33,9 +34,9 @@ func TestHelp(t *testing.T) {
 		action string
 		nlines int
 	}{
-		{"", 38},
-		{"up", 2},
-		{"down hup", 4},
+		{"", 126},
+		{"up", 10},
+		{"down hup", 11},
 		{"help", 2},
 		{"help exit", 3},
 	}
@@ -63,3 +64,21 @@ func TestHelp(t *testing.T) {
 
 	svctl.line.Close()
 }
+
+func TestRollingRestartParse(t *testing.T) {
+	cmd := &ctlCmdRollingRestart{}
+	svctl := &ctl{}
+	batch, pause, opts, names, err := cmd.parse(svctl, cmdSplit("rolling-restart --batch 2 --pause=5s -w 3 r*"))
+	if err != nil || batch != 2 || pause != 5*time.Second || opts.wait != 3*time.Second || !equal(names, []string{"r*"}) {
+		t.Errorf("ERROR IN PARSE: %d %s %+v %v (%v)", batch, pause, opts, names, err)
+	}
+	batch, pause, _, _, err = cmd.parse(svctl, cmdSplit("rolling-restart r*"))
+	if err != nil || batch != 1 || pause != 0 {
+		t.Errorf("ERROR IN DEFAULTS: %d %s (%v)", batch, pause, err)
+	}
+	for _, params := range []string{"rolling-restart --batch 0 r*", "rolling-restart --pause 5 r*", "rolling-restart --no-wait r*", "rolling-restart -w 0 r*"} {
+		if _, _, _, _, err := cmd.parse(svctl, cmdSplit(params)); err == nil {
+			t.Errorf("ERROR IN PARSE: expected error for `%s`", params)
+		}
+	}
+}
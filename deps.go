@@ -0,0 +1,305 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// needs Returns paths to services that service depends on.
+//
+// Dependencies are read from `needs` entry in config file (see configNames) or,
+// if there is none, from `needs` file in service directory,
+// one pattern per line. Patterns follow the same rules as NAMES,
+// except that unless prefixed with a label, they refer only to
+// the services directory holding service.
+func (c *ctl) needs(service string) ([]string, error) {
+	name := c.serviceName(service)
+	var patterns []string
+	ok := false
+	for _, key := range c.configNames(service) {
+		if patterns, ok = c.deps[key]; ok {
+			break
+		}
+	}
+	if !ok {
+		data, err := os.ReadFile(path.Join(service, "needs"))
+		if err != nil {
+			return nil, nil
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && line[0] != '#' {
+				patterns = append(patterns, line)
+			}
+		}
+	}
+
+	root, _, _ := c.rootName(service)
+	needs := []string{}
+	for _, pattern := range patterns {
+		services := c.Services(pattern, false)
+		if roots, _ := c.rootsOf(pattern); len(roots) > 1 {
+			services = c.inRoot(services, root)
+		}
+		if len(services) == 0 {
+			return nil, fmt.Errorf("%s: needs %s: unable to find service", name, pattern)
+		}
+		for _, dep := range services {
+			if !contains(needs, dep) {
+				needs = append(needs, dep)
+			}
+		}
+	}
+	return needs, nil
+}
+
+// inRoot Returns services that are in root.
+func (c *ctl) inRoot(services []string, root svRoot) []string {
+	found := []string{}
+	for _, service := range services {
+		if r, _, ok := c.rootName(service); ok && r == root {
+			found = append(found, service)
+		}
+	}
+	return found
+}
+
+// depsGraph Returns dependencies of services and all of their
+// (transitive) dependencies, as a map from service to its needs.
+//
+// If reverse is true, services that (transitively) depend on services
+// are gathered instead, but the map still points from service to its needs.
+func (c *ctl) depsGraph(services []string, reverse bool) (map[string][]string, error) {
+	graph := map[string][]string{}
+	if reverse {
+		all := map[string][]string{}
+		dependents := map[string][]string{}
+		// Labeled needs can point to other services directories, so all of them are scanned.
+		for _, service := range c.Services("*", false) {
+			// Broken needs of unrelated services should not matter here.
+			needs, _ := c.needs(service)
+			all[service] = needs
+			for _, dep := range needs {
+				dependents[dep] = append(dependents[dep], service)
+			}
+		}
+		queue := append([]string{}, services...)
+		for len(queue) > 0 {
+			service := queue[0]
+			queue = queue[1:]
+			if _, ok := graph[service]; ok {
+				continue
+			}
+			graph[service] = all[service]
+			queue = append(queue, dependents[service]...)
+		}
+		return graph, nil
+	}
+
+	queue := append([]string{}, services...)
+	for len(queue) > 0 {
+		service := queue[0]
+		queue = queue[1:]
+		if _, ok := graph[service]; ok {
+			continue
+		}
+		needs, err := c.needs(service)
+		if err != nil {
+			return nil, err
+		}
+		graph[service] = needs
+		queue = append(queue, needs...)
+	}
+	return graph, nil
+}
+
+// depsLayers Orders services in graph, so that each layer depends only on the previous ones.
+// Dependencies pointing outside of graph are ignored.
+// Returns error describing the cycle, if there is one.
+func (c *ctl) depsLayers(graph map[string][]string) ([][]string, error) {
+	done := map[string]bool{}
+	layers := [][]string{}
+	for len(done) < len(graph) {
+		layer := []string{}
+		for service, needs := range graph {
+			if done[service] {
+				continue
+			}
+			ready := true
+			for _, dep := range needs {
+				if _, ok := graph[dep]; ok && !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				layer = append(layer, service)
+			}
+		}
+		if len(layer) == 0 {
+			return nil, c.depsCycle(graph, done)
+		}
+		sort.Strings(layer)
+		for _, service := range layer {
+			done[service] = true
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+// depsCycle Returns error describing a cycle among services in graph that are not done.
+func (c *ctl) depsCycle(graph map[string][]string, done map[string]bool) error {
+	services := []string{}
+	for service := range graph {
+		if !done[service] {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+
+	// Every remaining service has a remaining dependency,
+	// so walking them has to come back eventually.
+	chain := []string{services[0]}
+	for {
+		var next string
+		for _, dep := range graph[chain[len(chain)-1]] {
+			if _, ok := graph[dep]; ok && !done[dep] {
+				next = dep
+				break
+			}
+		}
+		for i, service := range chain {
+			if service == next {
+				chain = append(chain[i:], next)
+				break
+			}
+		}
+		if chain[len(chain)-1] == next {
+			break
+		}
+		chain = append(chain, next)
+	}
+	names := make([]string, len(chain))
+	for i, service := range chain {
+		names[i] = c.serviceName(service)
+	}
+	return fmt.Errorf("dependency cycle: %s", strings.Join(names, " -> "))
+}
+
+// ctlDeps Delegates action to services, respecting their dependencies.
+//
+// When starting, dependencies are started first, when stopping,
+// services depending on the ones being stopped are stopped first.
+// Each layer has to reach desired state before the next one is touched,
+// even with --no-wait.
+func (c *ctl) ctlDeps(action []byte, services []string, start uint64, opts *ctlOpts) {
+	stop := action[0] == 'd'
+	graph, err := c.depsGraph(services, stop)
+	if err == nil {
+		var layers [][]string
+		if layers, err = c.depsLayers(graph); err == nil {
+			if stop {
+				for i, j := 0, len(layers)-1; i < j; i, j = i+1, j-1 {
+					layers[i], layers[j] = layers[j], layers[i]
+				}
+			}
+			services := []string{}
+			for service := range graph {
+				services = append(services, service)
+			}
+			sort.Strings(services)
+			if !c.denied(services) {
+				c.ctlLayers(action, layers, start, opts)
+			}
+			return
+		}
+	}
+	c.fail()
+	c.println(err)
+}
+
+// ctlLayers Delegates action to layers of services, one after another.
+// Stops at the first layer that does not reach desired state.
+func (c *ctl) ctlLayers(action []byte, layers [][]string, start uint64, opts *ctlOpts) {
+	reason := "not started, dependency failed"
+	if action[0] == 'd' {
+		reason = "not stopped, dependent service failed"
+	}
+	for i, layer := range layers {
+		layerOpts := opts
+		if i < len(layers)-1 && opts.noWait {
+			layerOpts = &ctlOpts{wait: opts.wait, verbose: opts.verbose, parallel: opts.parallel}
+		}
+		if failed := c.ctlAll(action, layer, start, layerOpts); len(failed) > 0 {
+			for _, rest := range layers[i+1:] {
+				for _, service := range rest {
+					c.fail()
+					c.printf("%s: %s\n", c.serviceName(service), reason)
+				}
+			}
+			return
+		}
+	}
+}
+
+// DepsTree Prints dependency tree of service.
+func (c *ctl) DepsTree(service string, graph map[string][]string) {
+	c.println(c.serviceName(service))
+	c.depsTree(graph[service], graph, "")
+}
+
+func (c *ctl) depsTree(services []string, graph map[string][]string, indent string) {
+	for i, service := range services {
+		branch, next := "├── ", "│   "
+		if i == len(services)-1 {
+			branch, next = "└── ", "    "
+		}
+		c.printf("%s%s%s\n", indent, branch, c.serviceName(service))
+		c.depsTree(graph[service], graph, indent+next)
+	}
+}
+
+// DepsDot Prints dependency graph in DOT format.
+func (c *ctl) DepsDot(graph map[string][]string) {
+	services := make([]string, 0, len(graph))
+	for service := range graph {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	c.println("digraph deps {")
+	for _, service := range services {
+		if len(graph[service]) == 0 {
+			c.printf("\t%q;\n", c.serviceName(service))
+		}
+		for _, dep := range graph[service] {
+			c.printf("\t%q -> %q;\n", c.serviceName(service), c.serviceName(dep))
+		}
+	}
+	c.println("}")
+}
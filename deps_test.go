@@ -0,0 +1,160 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestDeps(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	needs := map[string]string{
+		"web":   "api\ncache\n",
+		"api":   "# comment\ndb\n",
+		"cache": "",
+		"db":    "",
+		"cron":  "nope\n",
+		"a":     "b\n",
+		"b":     "c\n",
+		"c":     "a\n",
+	}
+	for name, data := range needs {
+		fatal(os.MkdirAll(path.Join(dir, name), 0755))
+		if data != "" {
+			fatal(os.WriteFile(path.Join(dir, name, "needs"), []byte(data), 0644))
+		}
+	}
+
+	stdout := &stdout{}
+	svctl := ctl{basedir: dir, stdout: stdout, deps: map[string][]string{"cache": {"db"}}}
+	assert := func(cmd string, failed bool, lines ...string) {
+		stdout.Clear()
+		svctl.failed = false
+		svctl.Ctl(cmd)
+		if svctl.Failed() != failed {
+			t.Errorf("ERROR IN FAILED: `%t` != `%t` for `%s`", svctl.Failed(), failed, cmd)
+		}
+		if !equal(stdout.value, lines) {
+			t.Errorf("ERROR IN OUTPUT: `%q` != `%q` for `%s`", stdout.value, lines, cmd)
+		}
+	}
+
+	assert("deps web", false,
+		"web",
+		"├── api",
+		"│   └── db",
+		"└── cache",
+		"    └── db",
+	)
+	assert("deps --dot api cache", false,
+		"digraph deps {",
+		`	"api" -> "db";`,
+		`	"cache" -> "db";`,
+		`	"db";`,
+		"}",
+	)
+	assert("deps cron", true, "cron: needs nope: unable to find service")
+	assert("deps b", true, "dependency cycle: a -> b -> c -> a")
+	assert("up a", true, "dependency cycle: a -> b -> c -> a")
+
+	graph, err := svctl.depsGraph([]string{path.Join(dir, "db")}, true)
+	if err != nil {
+		t.Errorf("ERROR IN REVERSE: %s", err)
+	}
+	layers, err := svctl.depsLayers(graph)
+	names := []string{}
+	for _, layer := range layers {
+		for i := range layer {
+			layer[i] = svctl.serviceName(layer[i])
+		}
+		names = append(names, strings.Join(layer, ","))
+	}
+	if err != nil || !equal(names, []string{"db", "api,cache", "web"}) {
+		t.Errorf("ERROR IN REVERSE: `%v` (%v)", names, err)
+	}
+
+	// There is no runsv, so db fails and nothing else should be touched.
+	assert("up web", true,
+		"db   ERROR   unable to open supervise/ok",
+		"api: not started, dependency failed",
+		"cache: not started, dependency failed",
+		"web: not started, dependency failed",
+	)
+}
+
+func TestDepsRoots(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	needs := map[string]string{
+		"sys/db":    "",
+		"sys/web":   "db\n",
+		"user/db":   "",
+		"user/app":  "db\n",
+		"user/sync": "sys:db\n",
+		"user/mail": "web\n",
+	}
+	for name, data := range needs {
+		fatal(os.MkdirAll(path.Join(dir, name), 0755))
+		if data != "" {
+			fatal(os.WriteFile(path.Join(dir, name, "needs"), []byte(data), 0644))
+		}
+	}
+	roots, err := parseRoots(path.Join(dir, "sys"), path.Join(dir, "user"))
+	fatal(err)
+
+	stdout := &stdout{}
+	svctl := ctl{stdout: stdout}
+	svctl.setRoots(roots)
+	assert := func(cmd string, failed bool, lines ...string) {
+		stdout.Clear()
+		svctl.failed = false
+		svctl.Ctl(cmd)
+		if svctl.Failed() != failed {
+			t.Errorf("ERROR IN FAILED: `%t` != `%t` for `%s`", svctl.Failed(), failed, cmd)
+		}
+		if !equal(stdout.value, lines) {
+			t.Errorf("ERROR IN OUTPUT: `%q` != `%q` for `%s`", stdout.value, lines, cmd)
+		}
+	}
+
+	assert("deps sys:web", false, "sys:web", "└── sys:db")
+	assert("deps user:app", false, "user:app", "└── user:db")
+	assert("deps user:sync", false, "user:sync", "└── sys:db")
+	assert("deps user:mail", true, "user:mail: needs web: unable to find service")
+
+	graph, err := svctl.depsGraph([]string{path.Join(dir, "sys/db")}, true)
+	services := []string{}
+	for service := range graph {
+		services = append(services, svctl.serviceName(service))
+	}
+	sort.Strings(services)
+	if err != nil || !equal(services, []string{"sys:db", "sys:web", "user:sync"}) {
+		t.Errorf("ERROR IN REVERSE: `%v` (%v)", services, err)
+	}
+}
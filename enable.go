@@ -0,0 +1,193 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// runsvDenied Returns whether we are not allowed to control service,
+// i.e. its supervise/ok cannot be opened for writing due to permissions.
+func runsvDenied(service string) bool {
+	f, err := os.OpenFile(
+		path.Join(service, "supervise/ok"), os.O_WRONLY|syscall.O_NONBLOCK, 0600,
+	)
+	if err != nil {
+		return os.IsPermission(err)
+	}
+	f.Close()
+	return false
+}
+
+// runsvAlive Returns whether there is a runsv process supervising service.
+func runsvAlive(service string) bool {
+	f, err := os.OpenFile(
+		path.Join(service, "supervise/ok"), os.O_WRONLY|syscall.O_NONBLOCK, 0600,
+	)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// Available Returns paths to service definitions in srcdir that are not
+// enabled, i.e. there is no service with the same name in basedir.
+func (c *ctl) Available() []string {
+	files, err := filepath.Glob(path.Join(c.srcdir, "*"))
+	if err != nil {
+		return nil
+	}
+	available := []string{}
+	for _, file := range files {
+		if fi, err := os.Stat(file); err != nil || !fi.IsDir() {
+			continue
+		}
+		if _, err := os.Lstat(path.Join(c.basedir, path.Base(file))); errors.Is(err, os.ErrNotExist) {
+			available = append(available, file)
+		}
+	}
+	sort.Strings(available)
+	return available
+}
+
+// Enable Enables service definitions in srcdir matching pattern
+// by symlinking them into basedir.
+func (c *ctl) Enable(pattern string) {
+	files, err := filepath.Glob(path.Join(c.srcdir, pattern))
+	if err != nil {
+		c.fail()
+		c.printf("%s: %s\n", pattern, err)
+		return
+	}
+	found := false
+	for _, file := range files {
+		if fi, err := os.Stat(file); err != nil || !fi.IsDir() {
+			continue
+		}
+		found = true
+
+		name := path.Base(file)
+		link := path.Join(c.basedir, name)
+		if dest, err := os.Readlink(link); err == nil && dest == file {
+			c.printf("%s: already enabled\n", name)
+			continue
+		}
+		if err := os.Symlink(file, link); err != nil {
+			c.fail()
+			if errors.Is(err, os.ErrExist) {
+				c.printf("%s: %s already exists\n", name, link)
+			} else {
+				c.printf("%s: unable to create link: %s\n", name, err)
+			}
+			continue
+		}
+		c.printf("%s: enabled\n", name)
+	}
+	if !found {
+		c.fail()
+		c.printf("%s: unable to find service definition in %s\n", pattern, c.srcdir)
+	}
+}
+
+// disable Stops service, waits for its runsv to exit and removes the link.
+//
+// If opts.noWait is set, the link is removed right away,
+// leaving it to runsvdir to terminate runsv.
+func (c *ctl) disable(service string, opts *ctlOpts) {
+	name := c.serviceName(service)
+	if fi, err := os.Lstat(service); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		c.fail()
+		c.printf("%s: not a symlink, refusing to remove\n", name)
+		return
+	}
+
+	if !opts.noWait && runsvAlive(service) {
+		// Once down, 'x' makes runsv exit. runsvdir would respawn it
+		// on its next scan, so the link has to go right after.
+		if opts.verbose {
+			c.printf("%s: sending 'dx'\n", name)
+		}
+		if err := c.control([]byte("dx"), service); err != nil {
+			c.fail()
+			c.println(err)
+			return
+		}
+		if opts.verbose {
+			c.printf("%s: waiting up to %s for runsv to exit\n", name, opts.wait)
+		}
+		timeout := time.After(opts.wait)
+		tick := time.NewTicker(100 * time.Millisecond)
+		defer tick.Stop()
+	wait:
+		for {
+			select {
+			case <-timeout:
+				c.fail()
+				c.printf("TIMEOUT: %s: runsv did not exit\n", name)
+				return
+			case <-tick.C:
+				if !runsvAlive(service) {
+					break wait
+				}
+			}
+		}
+	}
+
+	if err := os.Remove(service); err != nil {
+		c.fail()
+		c.printf("%s: unable to remove link: %s\n", name, err)
+		return
+	}
+	c.printf("%s: disabled\n", name)
+}
+
+// Disable Disables services matching pattern, see disable.
+func (c *ctl) Disable(pattern string, opts *ctlOpts) {
+	services := c.Services(pattern, false)
+	if len(services) == 0 {
+		c.fail()
+		c.printf("%s: unable to find service\n", pattern)
+		return
+	}
+	each(services, opts.parallel, func(service string) {
+		c.disable(service, opts)
+	})
+}
+
+// enableCompletions Returns names of available service definitions starting with prefix.
+func (c *ctl) enableCompletions(prefix string) []string {
+	compl := []string{}
+	for _, file := range c.Available() {
+		if name := path.Base(file); len(name) >= len(prefix) && name[:len(prefix)] == prefix {
+			compl = append(compl, fmt.Sprintf("%s ", name))
+		}
+	}
+	return compl
+}
@@ -0,0 +1,68 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestEnable(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	srcdir, basedir := path.Join(dir, "sv"), path.Join(dir, "service")
+	for _, name := range []string{"sv/nginx", "sv/api-eu", "sv/api-us", "service/local"} {
+		fatal(os.MkdirAll(path.Join(dir, name), 0755))
+	}
+
+	stdout := &stdout{}
+	svctl := ctl{basedir: basedir, srcdir: srcdir, stdout: stdout}
+	assert := func(cmd string, failed bool, lines ...string) {
+		stdout.Clear()
+		svctl.failed = false
+		svctl.Ctl(cmd)
+		if svctl.Failed() != failed {
+			t.Errorf("ERROR IN FAILED: `%t` != `%t` for `%s`", svctl.Failed(), failed, cmd)
+		}
+		if !equal(stdout.value, lines) {
+			t.Errorf("ERROR IN OUTPUT: `%v` != `%v` for `%s`", stdout.value, lines, cmd)
+		}
+	}
+
+	assert("list --available", false, "api-eu", "api-us", "nginx")
+	assert("enable api-* nope", true, "api-eu: enabled", "api-us: enabled", "nope: unable to find service definition in "+srcdir)
+	assert("enable api-eu", false, "api-eu: already enabled")
+	assert("list", false, "api-eu", "api-us", "local")
+	assert("list --available", false, "nginx")
+
+	head, compl, tail := svctl.completer("enable ", 7)
+	if head != "enable " || !equal(compl, []string{"nginx "}) || tail != "" {
+		t.Errorf("ERROR IN COMPLETION: `%s` `%v` `%s`", head, compl, tail)
+	}
+
+	assert("disable local", true, "local: not a symlink, refusing to remove")
+	assert("disable api-us", false, "api-us: disabled")
+	assert("list", false, "api-eu", "local")
+	assert("list --available", false, "api-us", "nginx")
+}
@@ -0,0 +1,80 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"time"
+)
+
+// forceSignals Names actions that force commands escalate through.
+var forceSignals = map[byte]string{'d': "TERM", 't': "TERM", 'k': "KILL"}
+
+// force Sends action to service and, if it does not reach the desired state
+// within grace period, escalates to KILL and waits up to opts.wait more.
+// Reports each step, the final state and how long it took.
+// Returns whether the service reached desired state.
+func (c *ctl) force(action []byte, service string, grace time.Duration, opts *ctlOpts) bool {
+	name := c.serviceName(service)
+	status := newStatus(service, name)
+	if status.Errored() {
+		c.fail()
+		c.println(status)
+		return false
+	}
+
+	begin := time.Now()
+	start := svNow()
+	c.printf("%s: sending %s\n", name, forceSignals[action[0]])
+	if err := c.control(action, service); err != nil {
+		c.fail()
+		c.println(err)
+		return false
+	}
+	status, ok := c.waitFor(action, service, start, grace)
+	if !ok {
+		c.printf("%s: still not done after %s, sending KILL\n", name, grace)
+		if err := c.control([]byte{'k'}, service); err != nil {
+			c.fail()
+			c.println(err)
+			return false
+		}
+		status, ok = c.waitFor(action, service, start, opts.wait)
+	}
+	took := time.Since(begin).Truncate(10 * time.Millisecond)
+	if !ok {
+		c.fail()
+		c.printf("%s%s (after %s)\n", timeoutPrefix(status), status, took)
+		return false
+	}
+	if status.Errored() {
+		c.fail()
+	}
+	c.printf("%s (took %s)\n", status, took)
+	return !status.Errored()
+}
+
+// Force Performs force for all services, see ctlAll.
+func (c *ctl) Force(action []byte, services []string, grace time.Duration, opts *ctlOpts) {
+	each(services, opts.parallel, func(service string) {
+		c.force(action, service, grace, opts)
+	})
+}
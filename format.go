@@ -0,0 +1,89 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/template"
+)
+
+// statusFormat Writes statuses to w in specified format.
+//
+// Supported formats are "json" (a single array), "csv" (with header)
+// and anything else is treated as a Go template, executed once per status,
+// e.g. `{{.Name}} {{.State}}`. Templates have access to all statusRecord fields.
+func statusFormat(w io.Writer, format string, statuses []*status) error {
+	records := make([]*statusRecord, len(statuses))
+	for i, status := range statuses {
+		records[i] = status.Record()
+	}
+
+	// Output is buffered, so that it reaches w in one piece.
+	var buf bytes.Buffer
+	switch format {
+	case "json":
+		if err := json.NewEncoder(&buf).Encode(records); err != nil {
+			return fmt.Errorf("unable to encode json: %s", err)
+		}
+	case "csv":
+		out := csv.NewWriter(&buf)
+		out.Write([]string{
+			"name", "state", "pid", "uptime", "paused", "want", "term", "normally", "read_only", "error",
+		})
+		for _, r := range records {
+			out.Write([]string{
+				r.Name,
+				r.State,
+				strconv.FormatUint(uint64(r.Pid), 10),
+				strconv.FormatUint(r.Uptime, 10),
+				strconv.FormatBool(r.Paused),
+				r.Want,
+				strconv.FormatBool(r.Term),
+				r.Normally,
+				strconv.FormatBool(r.ReadOnly),
+				r.Error,
+			})
+		}
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return fmt.Errorf("unable to encode csv: %s", err)
+		}
+	default:
+		tmpl, err := template.New("status").Parse(format)
+		if err != nil {
+			return fmt.Errorf("unable to parse format: %s", err)
+		}
+		for _, r := range records {
+			if err := tmpl.Execute(&buf, r); err != nil {
+				return fmt.Errorf("unable to execute format: %s", err)
+			}
+			buf.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
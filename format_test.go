@@ -0,0 +1,72 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStatusFormat(t *testing.T) {
+	sv := &svState{Time: svNow() - 10, Pid: 42, Want: 'u', State: 1, NormallyUp: true}
+	statuses := []*status{
+		{name: "r0", sv: sv, svStatus: "RUNNING"},
+		{name: "w", err: fmt.Errorf("unable to open supervise/ok")},
+	}
+
+	defs := []struct {
+		format string
+		output []string
+	}{
+		{"json", []string{
+			`[{"name":"r0","state":"RUNNING","pid":42,"uptime":10,"paused":false,"want":"up","term":false,"normally":"up","read_only":false,"error":""},` +
+				`{"name":"w","state":"ERROR","pid":0,"uptime":0,"paused":false,"want":"","term":false,"normally":"","read_only":false,"error":"unable to open supervise/ok"}]`,
+		}},
+		{"csv", []string{
+			"name,state,pid,uptime,paused,want,term,normally,read_only,error",
+			"r0,RUNNING,42,10,false,up,false,up,false,",
+			"w,ERROR,0,0,false,,false,,false,unable to open supervise/ok",
+		}},
+		{"{{.Name}} {{.State}} {{.Pid}}", []string{"r0 RUNNING 42", "w ERROR 0"}},
+	}
+
+	for _, def := range defs {
+		stdout := &stdout{}
+		if err := statusFormat(stdout, def.format, statuses); err != nil {
+			t.Errorf("ERROR IN FORMAT: `%s` for `%s`", err, def.format)
+			continue
+		}
+		// Second might have passed in between.
+		for i, line := range stdout.value {
+			line = strings.Replace(line, `"uptime":11,`, `"uptime":10,`, 1)
+			stdout.value[i] = strings.Replace(line, ",42,11,", ",42,10,", 1)
+		}
+		if !equal(stdout.value, def.output) {
+			t.Errorf("ERROR IN OUTPUT: `%v` != `%v` for `%s`", stdout.value, def.output, def.format)
+		}
+	}
+
+	if err := statusFormat(&stdout{}, "{{.Name", statuses); err == nil {
+		t.Errorf("ERROR IN FORMAT: expected error for invalid template")
+	}
+}
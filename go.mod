@@ -3,6 +3,7 @@ module github.com/KenjiTakahashi/svctl
 go 1.18
 
 require (
+	github.com/BurntSushi/toml v1.3.2
 	github.com/adrg/xdg v0.4.0
 	github.com/peterh/liner v1.2.2
 )
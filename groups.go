@@ -0,0 +1,116 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// groupsDir Is the location of directory with group files, relative to XDG config directories.
+// Each file defines a group named after it, one pattern per line.
+const groupsDir = "svctl/groups"
+
+// loadGroups Reads group files from XDG config directories. Groups defined
+// in config file take precedence, followed by user's and then system's files.
+func (c *ctl) loadGroups() {
+	for _, base := range append([]string{xdg.ConfigHome}, xdg.ConfigDirs...) {
+		c.loadGroupsDir(filepath.Join(base, groupsDir))
+	}
+}
+
+// loadGroupsDir Reads group files in dir, skipping groups defined already.
+func (c *ctl) loadGroupsDir(dir string) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		name := file.Name()
+		if _, ok := c.groups[name]; ok || file.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		fn := filepath.Join(dir, name)
+		data, err := os.ReadFile(fn)
+		if err != nil {
+			continue
+		}
+		patterns := []string{}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && line[0] != '#' {
+				patterns = append(patterns, line)
+			}
+		}
+		c.set("groups."+name, fn, func() { c.groups[name] = patterns })
+	}
+}
+
+// groupServices Returns paths to all services in group.
+//
+// Group consists of patterns, names of other groups (prefixed with '@')
+// and exclusions of either of these (prefixed with '!').
+// Exclusions apply to the whole group, regardless of their position.
+// visiting holds groups being currently resolved, to detect cycles.
+func (c *ctl) groupServices(name string, toLog bool, visiting []string) ([]string, error) {
+	patterns, ok := c.groups[name]
+	if !ok {
+		return nil, nil
+	}
+	if contains(visiting, name) {
+		return nil, fmt.Errorf("cycle @%s -> @%s", strings.Join(visiting, " -> @"), name)
+	}
+	visiting = append(visiting, name)
+
+	services := func(pattern string) ([]string, error) {
+		rest, predicates := stateSelector(pattern)
+		if !strings.HasPrefix(rest, "@") {
+			return c.Services(pattern, toLog), nil
+		}
+		dirs, err := c.groupServices(rest[1:], toLog, visiting)
+		return c.stateFilter(dirs, predicates), err
+	}
+
+	dirs, _, err := servicesOf(patterns, services)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// groupCompletions Returns names of groups (with '@') starting with prefix.
+func (c *ctl) groupCompletions(prefix string) []string {
+	compl := []string{}
+	for name := range c.groups {
+		if name = "@" + name; strings.HasPrefix(name, prefix) {
+			compl = append(compl, fmt.Sprintf("%s ", name))
+		}
+	}
+	sort.Strings(compl)
+	return compl
+}
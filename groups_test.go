@@ -0,0 +1,98 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+func TestGroups(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	basedir := path.Join(dir, "service")
+	for _, name := range []string{"nginx", "api-eu", "api-us", "api-legacy", "worker-mail", "db"} {
+		fatal(os.MkdirAll(path.Join(basedir, name), 0755))
+	}
+	fatal(os.MkdirAll(path.Join(dir, "svctl", "groups"), 0755))
+	fatal(os.WriteFile(
+		path.Join(dir, "svctl", "groups", "backend"),
+		[]byte("# comment\n\n@frontend\ndb\n!nginx\n"), 0644,
+	))
+	fatal(os.WriteFile(path.Join(dir, "svctl", "groups", "api"), []byte("nope\n"), 0644))
+
+	system := path.Join(dir, "system")
+	fatal(os.MkdirAll(path.Join(system, "svctl", "groups"), 0755))
+	fatal(os.WriteFile(path.Join(system, "svctl", "groups", "backend"), []byte("nginx\n"), 0644))
+	fatal(os.WriteFile(path.Join(system, "svctl", "groups", "ops"), []byte("db\n"), 0644))
+
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("XDG_CONFIG_DIRS", system)
+	xdg.Reload()
+	defer xdg.Reload()
+
+	svctl := ctl{basedir: basedir, sources: map[string]string{}, groups: map[string][]string{
+		"frontend": {"nginx", "api-*", "!api-legacy", "worker-mail"},
+		"api":      {"api-*"},
+		"a":        {"db", "@b"},
+		"b":        {"@a"},
+	}}
+	svctl.loadGroups()
+
+	defs := []struct {
+		pattern  string
+		services []string
+	}{
+		{"@frontend", []string{"api-eu", "api-us", "nginx", "worker-mail"}},
+		{"@api", []string{"api-eu", "api-legacy", "api-us"}},
+		{"@backend", []string{"api-eu", "api-us", "db", "worker-mail"}},
+		{"@ops", []string{"db"}},
+		{"@nope", []string{}},
+		{"@a", []string{}},
+	}
+	for _, def := range defs {
+		services := svctl.Services(def.pattern, false)
+		names := make([]string, len(services))
+		for i, service := range services {
+			names[i] = svctl.serviceName(service)
+		}
+		if !equal(names, def.services) {
+			t.Errorf("ERROR IN GROUP: `%v` != `%v` for `%s`", names, def.services, def.pattern)
+		}
+	}
+
+	_, compl, _ := svctl.completer("up @", 4)
+	if !equal(compl, []string{
+		"@a ", "@api ", "@b ", "@backend ", "@frontend ", "@ops ",
+		"@down ", "@errored ", "@finishing ", "@normally-down ", "@normally-up ", "@paused ", "@up ", "@want-up-but-down ",
+	}) {
+		t.Errorf("ERROR IN COMPLETION: `%v`", compl)
+	}
+	_, compl, _ = svctl.completer("up a", 4)
+	if !equal(compl, []string{"api-eu ", "api-legacy ", "api-us "}) {
+		t.Errorf("ERROR IN COMPLETION: `%v`", compl)
+	}
+}
@@ -0,0 +1,387 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// svlogdArgOpts Are svlogd options that take an argument.
+const svlogdArgOpts = "rRlb"
+
+// svlogdDir Returns directory svlogd writes to for given service.
+//
+// It is read from the svlogd invocation in service's log/run script
+// (or from run, if service is a log service itself). Relative paths
+// are resolved against the log service directory.
+func svlogdDir(service string) (string, error) {
+	logService := path.Join(service, "log")
+	if path.Base(service) == "log" {
+		logService = service
+	}
+	run, err := os.ReadFile(path.Join(logService, "run"))
+	if err != nil {
+		return "", fmt.Errorf("unable to read log/run")
+	}
+
+	for _, line := range strings.Split(string(run), "\n") {
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if path.Base(strings.Trim(field, "'\"")) != "svlogd" {
+				continue
+			}
+			for j := i + 1; j < len(fields); j++ {
+				arg := strings.Trim(fields[j], "'\"")
+				if arg == "" || strings.ContainsAny(arg[:1], "<>|;&#") {
+					break
+				}
+				if arg[0] == '-' {
+					if len(arg) == 2 && strings.ContainsRune(svlogdArgOpts, rune(arg[1])) {
+						j++
+					}
+					continue
+				}
+				if !path.IsAbs(arg) {
+					arg = path.Join(logService, arg)
+				}
+				return arg, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unable to find svlogd directory in log/run")
+}
+
+// tai64nParse Parses TAI64N label, as written by `svlogd -t`.
+func tai64nParse(label string) (time.Time, bool) {
+	if len(label) != 25 || label[0] != '@' {
+		return time.Time{}, false
+	}
+	secs, err := strconv.ParseUint(label[1:17], 16, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	nsecs, err := strconv.ParseUint(label[17:], 16, 32)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(secs-svTimeMod), int64(nsecs)), true
+}
+
+// logTimeFormat Is the format timestamps are displayed in.
+const logTimeFormat = "2006-01-02 15:04:05.000000"
+
+// tai64nLocal Replaces TAI64N label at the beginning of line with local time.
+// Lines without the label are returned unchanged.
+func tai64nLocal(line string) string {
+	label, rest, _ := strings.Cut(line, " ")
+	t, ok := tai64nParse(label)
+	if !ok {
+		return line
+	}
+	return fmt.Sprintf("%s %s", t.Local().Format(logTimeFormat), rest)
+}
+
+// logLineTime Splits line into its timestamp and message.
+//
+// Understands TAI64N labels (svlogd -t), as well as UTC timestamps
+// written by svlogd -tt and -ttt. Returns false if line has no timestamp.
+func logLineTime(line string) (time.Time, string, bool) {
+	label, rest, _ := strings.Cut(line, " ")
+	if t, ok := tai64nParse(label); ok {
+		return t, rest, true
+	}
+	for _, layout := range []string{"2006-01-02_15:04:05.999999999", "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, label); err == nil {
+			return t, rest, true
+		}
+	}
+	return time.Time{}, line, false
+}
+
+// logTime Parses time given either as a duration before now (e.g. "1h"),
+// or as absolute local time (e.g. "2006-01-02T15:04:05").
+func logTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s: invalid time", s)
+}
+
+// logFiles Returns all svlogd files in dir, oldest first.
+// These are the rotated ones (@*.s and @*.u) followed by current.
+func logFiles(dir string) []string {
+	files := []string{}
+	for _, pattern := range []string{"@*.[su]", "current"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		// TAI64N labels sort chronologically as strings.
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+	return files
+}
+
+// logEntry Represents a single line logged by a service.
+type logEntry struct {
+	Service string    `json:"service"`
+	Time    time.Time `json:"time"`
+	Line    string    `json:"line"`
+}
+
+// logQuery Defines which log entries should be reported by Logs.
+// Zero values mean no restriction.
+type logQuery struct {
+	since time.Time
+	until time.Time
+	grep  *regexp.Regexp
+}
+
+// Match Returns whether entry satisfies the query.
+func (q *logQuery) Match(e *logEntry) bool {
+	if !q.since.IsZero() && e.Time.Before(q.since) {
+		return false
+	}
+	if !q.until.IsZero() && e.Time.After(q.until) {
+		return false
+	}
+	return q.grep == nil || q.grep.MatchString(e.Line)
+}
+
+// logRead Reads all entries matching query from svlogd files in dir.
+// Lines without timestamp inherit the one of the preceding line.
+func logRead(service, dir string, query *logQuery) ([]*logEntry, error) {
+	files := logFiles(dir)
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no log files in %s", dir)
+	}
+	entries := []*logEntry{}
+	var last time.Time
+	for _, fn := range files {
+		f, err := os.Open(fn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open %s", fn)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			t, line, ok := logLineTime(scanner.Text())
+			if ok {
+				last = t
+			}
+			entry := &logEntry{Service: service, Time: last, Line: line}
+			if query.Match(entry) {
+				entries = append(entries, entry)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s", fn)
+		}
+	}
+	return entries, nil
+}
+
+// Logs Prints log entries of all services, merged by time into a single timeline.
+// If asJSON is true, entries are printed as JSON lines.
+func (c *ctl) Logs(services []string, query *logQuery, asJSON bool) {
+	entries := []*logEntry{}
+	width := 0
+	for _, service := range services {
+		name := c.serviceName(service)
+		dir, err := svlogdDir(service)
+		if err == nil {
+			var serviceEntries []*logEntry
+			serviceEntries, err = logRead(name, dir, query)
+			entries = append(entries, serviceEntries...)
+		}
+		if err != nil {
+			// Not on stdout, so that it does not break JSON output.
+			c.fail()
+			log.Printf("%s: %s\n", name, err)
+			continue
+		}
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(entries[j].Time)
+	})
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		if asJSON {
+			enc.Encode(entry)
+			continue
+		}
+		ts := ""
+		if !entry.Time.IsZero() {
+			ts = fmt.Sprintf("%s ", entry.Time.Local().Format(logTimeFormat))
+		}
+		fmt.Fprintf(&buf, "%-[1]*s%s%s\n", width+3, entry.Service, ts, entry.Line)
+	}
+	c.stdout.Write(buf.Bytes())
+}
+
+// logTail Returns last n lines of r.
+func logTail(r io.Reader, n int) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// logFollower Reads lines appended to svlogd's current file,
+// reopening it when svlogd rotates it away.
+// It starts reading from wherever f is positioned at.
+type logFollower struct {
+	fn      string
+	f       *os.File
+	partial []byte
+}
+
+// Lines Returns complete lines written since the last call.
+func (l *logFollower) Lines() []string {
+	lines := l.read()
+
+	fi, err := os.Stat(l.fn)
+	if err != nil {
+		// Probably in the middle of rotation, try again next time.
+		return lines
+	}
+	ofi, err := l.f.Stat()
+	if err == nil && os.SameFile(fi, ofi) {
+		if offset, err := l.f.Seek(0, io.SeekCurrent); err != nil || fi.Size() >= offset {
+			return lines
+		}
+	}
+
+	// Rotated (or truncated), rest of the old file was already read above.
+	f, err := os.Open(l.fn)
+	if err != nil {
+		return lines
+	}
+	l.f.Close()
+	l.f = f
+	l.partial = nil
+	return append(lines, l.read()...)
+}
+
+func (l *logFollower) read() []string {
+	data, _ := io.ReadAll(l.f)
+	if len(data) == 0 {
+		return nil
+	}
+	data = append(l.partial, data...)
+	i := bytes.LastIndexByte(data, '\n')
+	l.partial = append([]byte{}, data[i+1:]...)
+	if i < 0 {
+		return nil
+	}
+	return strings.Split(string(data[:i]), "\n")
+}
+
+// Close Closes currently followed file.
+func (l *logFollower) Close() {
+	l.f.Close()
+}
+
+// Log Prints last n lines of svlogd output for service.
+// If dir is empty, it is discovered using svlogdDir.
+// If follow is true, keeps printing new lines until a key is pressed.
+func (c *ctl) Log(service, dir string, n int, follow bool) {
+	name := c.serviceName(service)
+	if dir == "" {
+		var err error
+		if dir, err = svlogdDir(service); err != nil {
+			c.fail()
+			c.printf("%s: %s\n", name, err)
+			return
+		}
+	}
+	fn := filepath.Join(dir, "current")
+
+	f, err := os.Open(fn)
+	if err != nil {
+		c.fail()
+		c.printf("%s: unable to open %s\n", name, fn)
+		return
+	}
+	lines, err := logTail(f, n)
+	if err != nil {
+		f.Close()
+		c.fail()
+		c.printf("%s: unable to read %s\n", name, fn)
+		return
+	}
+	for _, line := range lines {
+		c.println(tai64nLocal(line))
+	}
+	if !follow {
+		f.Close()
+		return
+	}
+
+	// Tail left the file at its end, so follow just from there.
+	follower := &logFollower{fn: fn, f: f}
+	defer follower.Close()
+
+	pressed, restore := keyPress()
+	defer restore()
+	tick := time.NewTicker(250 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case <-pressed:
+			return
+		case <-tick.C:
+			for _, line := range follower.Lines() {
+				c.println(tai64nLocal(line))
+			}
+		}
+	}
+}
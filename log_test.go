@@ -0,0 +1,197 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSvlogdDir(t *testing.T) {
+	defs := []struct {
+		run string
+		dir string
+	}{
+		{"#!/bin/sh\nexec svlogd -tt /var/log/foo\n", "/var/log/foo"},
+		{"#!/bin/sh\nexec chpst -ulog svlogd -t ./main\n", "LOG/main"},
+		{"#!/bin/sh\nexec /usr/bin/svlogd -r _ -l 100 'main' 2>&1\n", "LOG/main"},
+		{"#!/bin/sh\nexec vlogger -t foo\n", ""},
+	}
+
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	fatal(os.MkdirAll(path.Join(dir, "foo", "log"), 0755))
+
+	for _, def := range defs {
+		fatal(os.WriteFile(path.Join(dir, "foo", "log", "run"), []byte(def.run), 0755))
+		expected := strings.Replace(def.dir, "LOG", path.Join(dir, "foo", "log"), 1)
+		for _, service := range []string{"foo", "foo/log"} {
+			logdir, err := svlogdDir(path.Join(dir, service))
+			if (err != nil) != (expected == "") {
+				t.Errorf("ERROR IN ERR: `%v` for `%q`", err, def.run)
+			}
+			if logdir != expected {
+				t.Errorf("ERROR IN DIR: `%s` != `%s` for `%q`", logdir, expected, def.run)
+			}
+		}
+	}
+
+	if _, err := svlogdDir(path.Join(dir, "bar")); err == nil {
+		t.Errorf("ERROR IN ERR: expected error for missing log/run")
+	}
+}
+
+func TestTai64n(t *testing.T) {
+	ts := time.Date(2023, 11, 12, 10, 20, 30, 123456000, time.UTC)
+	label := fmt.Sprintf("@%016x%08x", uint64(ts.Unix())+svTimeMod, ts.Nanosecond())
+
+	parsed, ok := tai64nParse(label)
+	if !ok || !parsed.Equal(ts) {
+		t.Errorf("ERROR IN PARSE: `%s` != `%s` for `%s`", parsed, ts, label)
+	}
+	for _, label := range []string{"", "@", "4000000065509b1e075bcd15x", "@4000000065509b1e075bcdzz"} {
+		if _, ok := tai64nParse(label); ok {
+			t.Errorf("ERROR IN PARSE: `%s` should not parse", label)
+		}
+	}
+
+	expected := ts.Local().Format("2006-01-02 15:04:05.000000") + " hello world"
+	if line := tai64nLocal(label + " hello world"); line != expected {
+		t.Errorf("ERROR IN LOCAL: `%s` != `%s`", line, expected)
+	}
+	if line := tai64nLocal("hello world"); line != "hello world" {
+		t.Errorf("ERROR IN LOCAL: `%s` != `hello world`", line)
+	}
+}
+
+func TestLogFollow(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	fn := path.Join(dir, "current")
+	fatal(os.WriteFile(fn, []byte("1\n2\n3\n"), 0644))
+
+	f, err := os.Open(fn)
+	fatal(err)
+	lines, err := logTail(f, 2)
+	if err != nil || !equal(lines, []string{"2", "3"}) {
+		t.Errorf("ERROR IN TAIL: `%v` (%v) != `[2 3]`", lines, err)
+	}
+
+	follower := &logFollower{fn: fn, f: f}
+	defer follower.Close()
+	appendLog := func(data string) {
+		f, err := os.OpenFile(fn, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+		fatal(err)
+		_, err = f.WriteString(data)
+		fatal(err)
+		f.Close()
+	}
+
+	appendLog("4\n5")
+	if lines := follower.Lines(); !equal(lines, []string{"4"}) {
+		t.Errorf("ERROR IN FOLLOW: `%v` != `[4]`", lines)
+	}
+	// Rotation, as done by svlogd.
+	appendLog("\n6\n")
+	fatal(os.Rename(fn, path.Join(dir, "@400000006550a0a100000000.s")))
+	appendLog("7\n")
+	if lines := follower.Lines(); !equal(lines, []string{"5", "6", "7"}) {
+		t.Errorf("ERROR IN FOLLOW: `%v` != `[5 6 7]`", lines)
+	}
+	if lines := follower.Lines(); len(lines) != 0 {
+		t.Errorf("ERROR IN FOLLOW: `%v` != `[]`", lines)
+	}
+}
+
+func TestLogs(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+
+	now := time.Now().Truncate(time.Second)
+	label := func(ago time.Duration) string {
+		ts := now.Add(-ago)
+		return fmt.Sprintf("@%016x%08x", uint64(ts.Unix())+svTimeMod, ts.Nanosecond())
+	}
+	files := map[string]string{
+		"a/log/main/@400000006550a0a100000000.s": fmt.Sprintf(
+			"%s a1\n%s a2 panic\n", label(3*time.Hour), label(50*time.Minute),
+		),
+		"a/log/main/current": fmt.Sprintf("%s a3\n", label(20*time.Minute)),
+		"b/log/main/current": fmt.Sprintf(
+			"%s b1 timeout\ncontinued\n%s b2\n", label(40*time.Minute), label(5*time.Minute),
+		),
+	}
+	for fn, data := range files {
+		fatal(os.MkdirAll(path.Join(dir, path.Dir(fn)), 0755))
+		fatal(os.WriteFile(path.Join(dir, fn), []byte(data), 0644))
+	}
+	for _, service := range []string{"a", "b"} {
+		fatal(os.WriteFile(path.Join(dir, service, "log", "run"), []byte("exec svlogd -t main\n"), 0755))
+	}
+
+	defs := []struct {
+		query string
+		lines []string
+	}{
+		{"logs a b", []string{"a1", "a2 panic", "b1 timeout", "continued", "a3", "b2"}},
+		{"logs * --since 1h --until=10m", []string{"a2 panic", "b1 timeout", "continued", "a3"}},
+		{"logs ? --grep panic|timeout", []string{"a2 panic", "b1 timeout"}},
+	}
+
+	for _, def := range defs {
+		stdout := &stdout{}
+		svctl := ctl{basedir: dir, stdout: stdout}
+		svctl.Ctl(def.query)
+		if svctl.Failed() {
+			t.Errorf("ERROR IN FAILED: for `%s`", def.query)
+		}
+		lines := make([]string, len(stdout.value))
+		for i, line := range stdout.value {
+			fields := strings.Fields(line)
+			if fields[0] != string(fields[3][0]) && fields[3] != "continued" {
+				t.Errorf("ERROR IN SERVICE: `%s` for `%s`", line, def.query)
+			}
+			lines[i] = strings.Join(fields[3:], " ")
+		}
+		if !equal(lines, def.lines) {
+			t.Errorf("ERROR IN LINES: `%v` != `%v` for `%s`", lines, def.lines, def.query)
+		}
+	}
+
+	stdout := &stdout{}
+	svctl := ctl{basedir: dir, stdout: stdout}
+	svctl.Ctl("logs b --json --since 10m")
+	expected := fmt.Sprintf(
+		`{"service":"b","time":"%s","line":"b2"}`,
+		now.Add(-5*time.Minute).Format(time.RFC3339Nano),
+	)
+	if !equal(stdout.value, []string{expected}) {
+		t.Errorf("ERROR IN JSON: `%v` != `%s`", stdout.value, expected)
+	}
+}
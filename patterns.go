@@ -0,0 +1,184 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// braceExpand Expands shell-style braces in pattern, e.g. `{nginx,haproxy}-*`
+// becomes `nginx-*` and `haproxy-*`. Braces without a comma are left as they are.
+func braceExpand(pattern string) []string {
+	depth, open, commas := 0, -1, []int{}
+	for i, r := range pattern {
+		switch r {
+		case '{':
+			if depth == 0 {
+				open, commas = i, commas[:0]
+			}
+			depth++
+		case ',':
+			if depth == 1 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth == 0 {
+				continue
+			}
+			depth--
+			if depth > 0 || len(commas) == 0 {
+				continue
+			}
+			prefix, suffix := pattern[:open], pattern[i+1:]
+			bounds := append(append([]int{open}, commas...), i)
+			expanded := []string{}
+			for j := 1; j < len(bounds); j++ {
+				alternative := pattern[bounds[j-1]+1 : bounds[j]]
+				expanded = append(expanded, braceExpand(prefix+alternative+suffix)...)
+			}
+			return expanded
+		}
+	}
+	return []string{pattern}
+}
+
+// regexServices Returns paths to all services with names matching regular expression.
+// Nested services are matched as well, by their names relative to services directory
+// (e.g. `tenants/acme/api`), see recursiveServices.
+func (c *ctl) regexServices(expr string, toLog bool) []string {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		log.Printf("error compiling regular expression: %s\n", err)
+		return nil
+	}
+	candidates := c.Services("*", toLog)
+	for _, dir := range c.Services("**", toLog) {
+		if !contains(candidates, dir) {
+			candidates = append(candidates, dir)
+		}
+	}
+	dirs := []string{}
+	for _, dir := range candidates {
+		if re.MatchString(c.serviceName(dir)) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// servicesOf Returns unique services matching patterns, as resolved by services,
+// and patterns that did not match anything.
+//
+// Patterns starting with '!' exclude matching services from the result,
+// regardless of their position.
+func servicesOf(patterns []string, services func(pattern string) ([]string, error)) ([]string, []string, error) {
+	included, excluded, unmatched := []string{}, []string{}, []string{}
+	for _, pattern := range patterns {
+		exclude := strings.HasPrefix(pattern, "!")
+		dirs, err := services(strings.TrimPrefix(pattern, "!"))
+		if err != nil {
+			return nil, nil, err
+		}
+		if exclude {
+			excluded = append(excluded, dirs...)
+			continue
+		}
+		if len(dirs) == 0 {
+			unmatched = append(unmatched, pattern)
+		}
+		included = append(included, dirs...)
+	}
+
+	dirs := []string{}
+	for _, dir := range included {
+		if !contains(excluded, dir) && !contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, unmatched, nil
+}
+
+// ServicesOf Returns unique services matching patterns (all, if there are none
+// or all of them are exclusions), see servicesOf.
+// Reports patterns that do not match anything, if report is true.
+func (c *ctl) ServicesOf(patterns []string, toLog, report bool) []string {
+	all := true
+	for _, pattern := range patterns {
+		all = all && strings.HasPrefix(pattern, "!")
+	}
+	if all {
+		patterns = append([]string{"*"}, patterns...)
+	}
+	dirs, unmatched, _ := servicesOf(patterns, func(pattern string) ([]string, error) {
+		return c.Services(pattern, toLog), nil
+	})
+	if report {
+		for _, pattern := range unmatched {
+			c.fail()
+			c.printf("%s: unable to find service\n", pattern)
+		}
+	}
+	return dirs
+}
+
+// nameCompletions Returns service names and other NAMES forms starting with prefix.
+func (c *ctl) nameCompletions(prefix string) []string {
+	if strings.HasPrefix(prefix, "!") {
+		compl := c.nameCompletions(prefix[1:])
+		for i, name := range compl {
+			compl[i] = "!" + name
+		}
+		return compl
+	}
+	if strings.HasPrefix(prefix, "re:") {
+		return []string{}
+	}
+	if i := strings.LastIndexAny(prefix, "{,"); i != -1 && strings.Contains(prefix[:i+1], "{") && !strings.Contains(prefix[i:], "}") {
+		// Inside braces, only the current alternative is completed
+		// and the braces are left for user to close.
+		compl := []string{}
+		for _, service := range c.Services(prefix[i+1:]+"*", true) {
+			compl = append(compl, prefix[:i+1]+c.serviceName(service))
+		}
+		sort.Strings(compl)
+		return compl
+	}
+
+	services := c.Services(fmt.Sprintf("%s*", prefix), true)
+	compl := make([]string, len(services))
+	for i, service := range services {
+		compl[i] = fmt.Sprintf("%s ", c.serviceName(service))
+	}
+	if roots := c.Roots(); len(roots) > 1 && !strings.Contains(prefix, ":") {
+		for _, root := range roots {
+			if strings.HasPrefix(root.label, prefix) {
+				compl = append(compl, root.label+":")
+			}
+		}
+	}
+	compl = append(compl, c.groupCompletions(prefix)...)
+	return append(compl, stateCompletions(prefix)...)
+}
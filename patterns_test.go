@@ -0,0 +1,103 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestBraceExpand(t *testing.T) {
+	defs := []struct {
+		pattern  string
+		expanded []string
+	}{
+		{"nginx", []string{"nginx"}},
+		{"{nginx,haproxy}", []string{"nginx", "haproxy"}},
+		{"api-{eu,us}-{1,2}", []string{"api-eu-1", "api-eu-2", "api-us-1", "api-us-2"}},
+		{"{a,b{c,d}}x", []string{"ax", "bcx", "bdx"}},
+		{"{a}", []string{"{a}"}},
+		{"{a,b", []string{"{a,b"}},
+		{"a}{b,c}", []string{"a}b", "a}c"}},
+	}
+	for _, def := range defs {
+		if expanded := braceExpand(def.pattern); !equal(expanded, def.expanded) {
+			t.Errorf("ERROR IN EXPAND: `%v` != `%v` for `%s`", expanded, def.expanded, def.pattern)
+		}
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"api-eu", "api-us", "api-legacy", "nginx", "haproxy", "haproxy/log"} {
+		fatal(os.MkdirAll(path.Join(dir, name), 0755))
+	}
+
+	stdout := &stdout{}
+	svctl := ctl{basedir: dir, stdout: stdout, groups: map[string][]string{"lb": {"{nginx,haproxy}"}}}
+	defs := []struct {
+		patterns []string
+		services []string
+	}{
+		{[]string{"re:^api-(eu|us)$"}, []string{"api-eu", "api-us"}},
+		{[]string{"re:^h"}, []string{"haproxy"}},
+		{[]string{"api-*", "!api-legacy"}, []string{"api-eu", "api-us"}},
+		{[]string{"!api-legacy", "api-*"}, []string{"api-eu", "api-us"}},
+		{[]string{"!api-*", "!@lb"}, []string{}},
+		{[]string{"!re:^(api|nginx)"}, []string{"haproxy"}},
+		{[]string{"{nginx,haproxy}"}, []string{"nginx", "haproxy"}},
+		{[]string{"@lb", "!nginx"}, []string{"haproxy"}},
+		{[]string{"re:("}, []string{}},
+	}
+	for _, def := range defs {
+		services := svctl.ServicesOf(def.patterns, false, false)
+		names := make([]string, len(services))
+		for i, service := range services {
+			names[i] = svctl.serviceName(service)
+		}
+		if !equal(names, def.services) {
+			t.Errorf("ERROR IN PATTERNS: `%v` != `%v` for `%v`", names, def.services, def.patterns)
+		}
+	}
+
+	svctl.ServicesOf([]string{"api-*", "nope", "!nope"}, false, true)
+	if !equal(stdout.value, []string{"nope: unable to find service"}) || !svctl.Failed() {
+		t.Errorf("ERROR IN REPORT: `%v`", stdout.value)
+	}
+
+	compls := []struct {
+		prefix string
+		compl  []string
+	}{
+		{"!api-l", []string{"!api-legacy "}},
+		{"{nginx,h", []string{"{nginx,haproxy", "{nginx,haproxy/log"}},
+		{"re:^a", []string{}},
+	}
+	for _, def := range compls {
+		if compl := svctl.nameCompletions(def.prefix); compl == nil || !equal(compl, def.compl) {
+			t.Errorf("ERROR IN COMPLETION: `%v` != `%v` for `%s`", compl, def.compl, def.prefix)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// RollingRestart Restarts services in batches of batch services.
+//
+// Each batch has to get back up (see svCheck) before the next one starts,
+// with pause in between. If any service in a batch fails, stops right away
+// and reports services that were left untouched.
+func (c *ctl) RollingRestart(services []string, batch int, pause time.Duration, opts *ctlOpts) {
+	action := (&cmdRestart{}).Action()
+	for i := 0; i < len(services); i += batch {
+		end := i + batch
+		if end > len(services) {
+			end = len(services)
+		}
+		if i > 0 && pause > 0 {
+			if opts.verbose {
+				c.printf("pausing for %s\n", pause)
+			}
+			time.Sleep(pause)
+		}
+		if failed := c.ctlAll(action, services[i:end], svNow(), opts); len(failed) > 0 {
+			if end < len(services) {
+				untouched := make([]string, len(services)-end)
+				for j, service := range services[end:] {
+					untouched[j] = c.serviceName(service)
+				}
+				c.printf("not restarted: %s\n", strings.Join(untouched, ", "))
+			}
+			return
+		}
+	}
+}
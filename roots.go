@@ -0,0 +1,144 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// svRoot Is a single services directory, labeled when there are more of them.
+type svRoot struct {
+	label string
+	dir   string
+}
+
+// parseRoots Parses services directories, given as `[LABEL=]DIR` entries,
+// each of which can be a colon-separated list as well.
+// Directories without a label are labeled with their base name.
+func parseRoots(entries ...string) ([]svRoot, error) {
+	roots := []svRoot{}
+	labels := map[string]bool{}
+	for _, entry := range entries {
+		for _, part := range strings.Split(entry, ":") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			label, dir := "", part
+			if i := strings.IndexByte(part, '='); i != -1 {
+				label, dir = part[:i], part[i+1:]
+				if label == "" || strings.ContainsAny(label, "/@!*?[{") {
+					return nil, fmt.Errorf("%s: invalid label", label)
+				}
+				if labels[label] {
+					return nil, fmt.Errorf("%s: duplicate label", label)
+				}
+			}
+			if strings.HasPrefix(dir, "~/") {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return nil, fmt.Errorf("%s: unable to find home directory", dir)
+				}
+				dir = path.Join(home, dir[2:])
+			}
+			dir = path.Clean(dir)
+			if label == "" {
+				label = path.Base(dir)
+				for i := 2; labels[label]; i++ {
+					label = fmt.Sprintf("%s%d", path.Base(dir), i)
+				}
+			}
+			labels[label] = true
+			roots = append(roots, svRoot{label, dir})
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("no services directory")
+	}
+	return roots, nil
+}
+
+// setRoots Makes roots the services directories.
+// The first of them is used wherever a single one is needed, e.g. by enable.
+func (c *ctl) setRoots(roots []svRoot) {
+	c.basedir = roots[0].dir
+	c.roots = nil
+	if len(roots) > 1 {
+		c.roots = roots
+	}
+}
+
+// Roots Returns all services directories.
+// When there is just one, it is not labeled.
+func (c *ctl) Roots() []svRoot {
+	if len(c.roots) == 0 {
+		return []svRoot{{"", c.basedir}}
+	}
+	return c.roots
+}
+
+// rootsOf Returns services directories pattern refers to, along with
+// the rest of the pattern, relative to them.
+//
+// With more than one directory, pattern can be prefixed with a label
+// (e.g. `sys:nginx`), otherwise it refers to all of them.
+func (c *ctl) rootsOf(pattern string) ([]svRoot, string) {
+	roots := c.Roots()
+	if i := strings.IndexByte(pattern, ':'); i != -1 && len(roots) > 1 {
+		for _, root := range roots {
+			if root.label == pattern[:i] {
+				return []svRoot{root}, pattern[i+1:]
+			}
+		}
+	}
+	for _, root := range roots {
+		if pattern == root.dir || strings.HasPrefix(pattern, root.dir+"/") {
+			return []svRoot{root}, strings.TrimPrefix(pattern[len(root.dir):], "/")
+		}
+	}
+	return roots, pattern
+}
+
+// rootName Returns services directory containing dir and the name
+// of dir relative to it.
+func (c *ctl) rootName(dir string) (svRoot, string, bool) {
+	for _, root := range c.Roots() {
+		name, err := filepath.Rel(root.dir, dir)
+		if err == nil && name != ".." && !strings.HasPrefix(name, "../") {
+			return root, name, true
+		}
+	}
+	return svRoot{}, "", false
+}
+
+// configNames Returns names config entries of service can be keyed by,
+// i.e. its name, followed by the name without label, if there is one.
+func (c *ctl) configNames(service string) []string {
+	names := []string{c.serviceName(service)}
+	if root, name, ok := c.rootName(service); ok && root.label != "" {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,116 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestParseRoots(t *testing.T) {
+	home, err := os.UserHomeDir()
+	fatal(err)
+	roots, err := parseRoots("sys=/var/service:~/service", "/other/service/")
+	expected := []svRoot{{"sys", "/var/service"}, {"service", path.Join(home, "service")}, {"service2", "/other/service"}}
+	if err != nil || len(roots) != len(expected) {
+		t.Fatalf("ERROR IN ROOTS: `%v` != `%v` (%v)", roots, expected, err)
+	}
+	for i := range expected {
+		if roots[i] != expected[i] {
+			t.Errorf("ERROR IN ROOT: `%v` != `%v`", roots[i], expected[i])
+		}
+	}
+	for _, entries := range []string{"", "a=/x:a=/y", "=/x", "a*=/x"} {
+		if _, err := parseRoots(entries); err == nil {
+			t.Errorf("ERROR IN ROOTS: expected error for `%s`", entries)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	for _, service := range []string{"sys/nginx", "sys/ntpd", "user/nginx", "user/syncthing"} {
+		fatal(os.MkdirAll(path.Join(dir, service, "supervise"), 0755))
+	}
+
+	svctl := &ctl{}
+	svctl.setRoots([]svRoot{{"sys", path.Join(dir, "sys")}, {"user", path.Join(dir, "user")}})
+	defs := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"nginx", []string{"sys:nginx", "user:nginx"}},
+		{"*", []string{"sys:nginx", "sys:ntpd", "user:nginx", "user:syncthing"}},
+		{"user:*", []string{"user:nginx", "user:syncthing"}},
+		{"sys:n*", []string{"sys:nginx", "sys:ntpd"}},
+		{"**/sync*", []string{"user:syncthing"}},
+		{"sys:**", []string{"sys:nginx", "sys:ntpd"}},
+	}
+	for _, def := range defs {
+		names := []string{}
+		for _, dir := range svctl.Services(def.pattern, false) {
+			names = append(names, svctl.serviceName(dir))
+		}
+		if !equal(names, def.expected) {
+			t.Errorf("ERROR IN SERVICES: `%v` != `%v` for `%s`", names, def.expected, def.pattern)
+		}
+	}
+
+	statuses := svctl.StatusesOf([]string{"user:sync*"}, false)
+	if len(statuses) != 1 || statuses[0].name != "user:syncthing" {
+		t.Errorf("ERROR IN STATUSES: `%v`", statuses)
+	}
+	svctl.deps = map[string][]string{"nginx": {"ntpd"}, "user:nginx": {"syncthing"}}
+	svctl.sequences = map[string]map[string]string{"stop-sequence": {"nginx": "QUIT", "user:nginx": "INT"}}
+	configs := []struct {
+		service string
+		needs   []string
+		signal  string
+	}{
+		{"sys/nginx", []string{"sys:ntpd"}, "QUIT"},
+		{"user/nginx", []string{"user:syncthing"}, "INT"},
+	}
+	for _, def := range configs {
+		needs, err := svctl.needs(path.Join(dir, def.service))
+		names := []string{}
+		for _, dep := range needs {
+			names = append(names, svctl.serviceName(dep))
+		}
+		if err != nil || !equal(names, def.needs) {
+			t.Errorf("ERROR IN NEEDS: `%v` != `%v` for `%s` (%v)", names, def.needs, def.service, err)
+		}
+		steps, err := svctl.sequence([]byte("d"), path.Join(dir, def.service))
+		if err != nil || len(steps) == 0 || steps[0].signal != def.signal {
+			t.Errorf("ERROR IN SEQUENCE: `%v` != `%s` for `%s` (%v)", steps, def.signal, def.service, err)
+		}
+	}
+
+	if compl := svctl.nameCompletions("us"); !equal(compl, []string{"user:"}) {
+		t.Errorf("ERROR IN COMPLETIONS: `%v`", compl)
+	}
+	if setting := rootsString(svctl.Roots()); setting != "sys="+path.Join(dir, "sys")+":user="+path.Join(dir, "user") {
+		t.Errorf("ERROR IN SETTING: `%s`", setting)
+	}
+}
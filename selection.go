@@ -0,0 +1,109 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// exclusionsOnly Returns whether patterns do not include anything by themselves,
+// i.e. there are none or all of them are exclusions.
+func exclusionsOnly(patterns []string) bool {
+	for _, pattern := range patterns {
+		if !strings.HasPrefix(pattern, "!") {
+			return false
+		}
+	}
+	return true
+}
+
+// Select Changes the selection according to patterns.
+//
+// Patterns prefixed with '+' add matching services to the selection
+// and ones prefixed with '-' remove them from it. If there are any other
+// patterns, services matching them replace the selection first.
+// Without patterns, clears the selection.
+func (c *ctl) Select(patterns []string) {
+	replace, add, remove := []string{}, []string{}, []string{}
+	for _, pattern := range patterns {
+		switch {
+		case strings.HasPrefix(pattern, "+"):
+			add = append(add, pattern[1:])
+		case strings.HasPrefix(pattern, "-"):
+			remove = append(remove, pattern[1:])
+		default:
+			replace = append(replace, pattern)
+		}
+	}
+	if len(replace) > 0 || len(patterns) == 0 {
+		c.selection = nil
+	}
+	for _, patterns := range [][]string{replace, add} {
+		if len(patterns) == 0 {
+			continue
+		}
+		for _, service := range c.ServicesOf(patterns, false, true) {
+			if !contains(c.selection, service) {
+				c.selection = append(c.selection, service)
+			}
+		}
+	}
+	if len(remove) > 0 {
+		removed := c.ServicesOf(remove, false, true)
+		selection := []string{}
+		for _, service := range c.selection {
+			if !contains(removed, service) {
+				selection = append(selection, service)
+			}
+		}
+		c.selection = selection
+	}
+}
+
+// selected Returns unique services matching patterns, see ServicesOf.
+// When patterns do not include anything by themselves (see exclusionsOnly)
+// and there is a selection, the selected services (with their log services,
+// if toLog is true) are returned instead of all, without the excluded ones.
+func (c *ctl) selected(patterns []string, toLog, report bool) []string {
+	if !exclusionsOnly(patterns) || len(c.selection) == 0 {
+		return c.ServicesOf(patterns, toLog, report)
+	}
+	excluded := []string{}
+	for _, pattern := range patterns {
+		excluded = append(excluded, c.Services(pattern[1:], toLog)...)
+	}
+	dirs := []string{}
+	add := func(dir string) {
+		if fi, err := os.Stat(dir); err == nil && fi.IsDir() && !contains(excluded, dir) && !contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, service := range c.selection {
+		add(service)
+		if toLog && path.Base(service) != "log" {
+			add(path.Join(service, "log"))
+		}
+	}
+	return dirs
+}
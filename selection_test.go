@@ -0,0 +1,135 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"api", "app", "db", "db/log", "web", "tenants/acme/web"} {
+		fakeService(dir, name, fakeStatus(1, 1, false, 0, 1), false)
+	}
+
+	stdout := &stdout{}
+	svctl := &ctl{stdout: stdout, basedir: dir}
+	names := func(services []string) []string {
+		names := []string{}
+		for _, service := range services {
+			names = append(names, svctl.serviceName(service))
+		}
+		return names
+	}
+
+	if services := svctl.findServices(nil, &ctlOpts{}); len(services) != 0 || !svctl.failed {
+		t.Errorf("ERROR IN DEFAULT: `%v` selected without selection", names(services))
+	}
+	if !equal(stdout.value, []string{"no services given: specify NAMES ('*' for all) or select some first"}) {
+		t.Errorf("ERROR IN DEFAULT: `%v`", stdout.value)
+	}
+	stdout.Clear()
+	if services := svctl.findServices([]string{"!web"}, &ctlOpts{}); len(services) != 0 {
+		t.Errorf("ERROR IN DEFAULT: `%v` selected by exclusion", names(services))
+	}
+	stdout.Clear()
+
+	for _, cmd := range []string{"down", "force-stop", "rolling-restart", "signal TERM", "rotate", "logs"} {
+		svctl.Ctl(cmd)
+		if !equal(stdout.value, []string{"no services given: specify NAMES ('*' for all) or select some first"}) {
+			t.Errorf("ERROR IN DEFAULT: `%v` for `%s`", stdout.value, cmd)
+		}
+		stdout.Clear()
+	}
+
+	defs := []struct {
+		cmd      string
+		expected []string
+	}{
+		{"select a*", []string{"api", "app"}},
+		{"select +db +nope", []string{"api", "app", "db"}},
+		{"select -app", []string{"api", "db"}},
+		{"select web +api", []string{"web", "api"}},
+		{"select", []string{}},
+		{"select a* -api +w*", []string{"app", "web"}},
+	}
+	for _, def := range defs {
+		svctl.Ctl(def.cmd)
+		if selection := names(svctl.selection); !equal(selection, def.expected) {
+			t.Errorf("ERROR IN SELECTION: `%v` != `%v` for `%s`", selection, def.expected, def.cmd)
+		}
+	}
+	if !equal(stdout.value, []string{"nope: unable to find service"}) {
+		t.Errorf("ERROR IN SELECT: `%v`", stdout.value)
+	}
+	stdout.Clear()
+
+	svctl.Ctl("select db web")
+	if services := names(svctl.findServices(nil, &ctlOpts{withLog: true})); !equal(services, []string{"db", "db/log", "web"}) {
+		t.Errorf("ERROR IN DEFAULT: `%v` is not the selection", services)
+	}
+	if services := names(svctl.findServices([]string{"!web"}, &ctlOpts{})); !equal(services, []string{"db"}) {
+		t.Errorf("ERROR IN DEFAULT: `%v` is not the selection without web", services)
+	}
+	if services := names(svctl.findServices([]string{"a*"}, &ctlOpts{})); !equal(services, []string{"api", "app"}) {
+		t.Errorf("ERROR IN NAMES: `%v` != NAMES", services)
+	}
+	svctl.Ctl("select db/log **/acme/web")
+	if services := names(svctl.findServices(nil, &ctlOpts{})); !equal(services, []string{"db/log", "tenants/acme/web"}) {
+		t.Errorf("ERROR IN DEFAULT: `%v` is not the selection", services)
+	}
+	if services := names(svctl.selected([]string{"!db/*"}, true, false)); !equal(services, []string{"tenants/acme/web"}) {
+		t.Errorf("ERROR IN DEFAULT: `%v` is not the selection without db/log", services)
+	}
+	svctl.Ctl("select db web")
+	fatal(os.WriteFile(path.Join(dir, "db/log/supervise/control"), nil, 0644))
+	svctl.Ctl("rotate")
+	if control, _ := os.ReadFile(path.Join(dir, "db/log/supervise/control")); string(control) != "a" {
+		t.Errorf("ERROR IN DEFAULT: `%q` sent to selected db/log", control)
+	}
+	stdout.Clear()
+	statuses := svctl.StatusesOf(nil, true)
+	if len(statuses) != 3 || statuses[0].name != "db" || statuses[1].name != "db/log" || statuses[2].name != "web" {
+		t.Errorf("ERROR IN STATUSES: `%v`", statuses)
+	}
+
+	svctl.Ctl("selection")
+	if stdout.Len() != 2 {
+		t.Errorf("ERROR IN SELECTION: `%v`", stdout.value)
+	}
+	stdout.Clear()
+	svctl.Ctl("select")
+	svctl.Ctl("selection")
+	if !equal(stdout.value, []string{"no services selected"}) {
+		t.Errorf("ERROR IN SELECTION: `%v`", stdout.value)
+	}
+	if _, compl, _ := svctl.completer("select +we", 10); !equal(compl, []string{"+web "}) {
+		t.Errorf("ERROR IN COMPLETIONS: `%v`", compl)
+	}
+	if _, compl, _ := svctl.completer("select -we", 10); !equal(compl, []string{"-web "}) {
+		t.Errorf("ERROR IN COMPLETIONS: `%v`", compl)
+	}
+}
@@ -0,0 +1,136 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// stateSelectors Maps state selectors (without '@') to predicates
+// that statuses have to satisfy to be selected.
+var stateSelectors = map[string]func(s *status) bool{
+	"up": func(s *status) bool {
+		return !s.Errored() && s.sv.Pid != 0
+	},
+	"down": func(s *status) bool {
+		return !s.Errored() && s.sv.State == 0
+	},
+	"paused": func(s *status) bool {
+		return !s.Errored() && s.sv.Pid != 0 && s.sv.Paused
+	},
+	"errored": func(s *status) bool {
+		return s.Errored()
+	},
+	"finishing": func(s *status) bool {
+		return !s.Errored() && s.sv.State == 2
+	},
+	"want-up-but-down": func(s *status) bool {
+		return !s.Errored() && s.sv.Want == 'u' && s.sv.State == 0
+	},
+	"normally-up": func(s *status) bool {
+		return !s.Errored() && s.sv.NormallyUp
+	},
+	"normally-down": func(s *status) bool {
+		return !s.Errored() && !s.sv.NormallyUp
+	},
+}
+
+// statePredicate Returns predicate for state selector (without '@'),
+// either one of stateSelectors or uptime comparison, e.g. `uptime<60s`.
+func statePredicate(selector string) (func(s *status) bool, bool) {
+	if predicate, ok := stateSelectors[selector]; ok {
+		return predicate, true
+	}
+	if !strings.HasPrefix(selector, "uptime") || len(selector) < len("uptime")+2 {
+		return nil, false
+	}
+	op, value := selector[len("uptime")], selector[len("uptime")+1:]
+	limit, err := time.ParseDuration(value)
+	if err != nil {
+		if limit, err = cmdSeconds(value); err != nil {
+			return nil, false
+		}
+	}
+	switch op {
+	case '<':
+		return func(s *status) bool { return !s.Errored() && s.sv.Uptime() < limit }, true
+	case '>':
+		return func(s *status) bool { return !s.Errored() && s.sv.Uptime() > limit }, true
+	}
+	return nil, false
+}
+
+// stateSelector Splits trailing state selectors off pattern, e.g. `web*@down`.
+// Returns the rest of pattern and predicates of the selectors, if any.
+//
+// State selectors take precedence over groups of the same name.
+func stateSelector(pattern string) (string, []func(s *status) bool) {
+	predicates := []func(s *status) bool{}
+	for {
+		i := strings.LastIndexByte(pattern, '@')
+		if i == -1 {
+			break
+		}
+		predicate, ok := statePredicate(pattern[i+1:])
+		if !ok {
+			break
+		}
+		predicates = append(predicates, predicate)
+		pattern = pattern[:i]
+	}
+	return pattern, predicates
+}
+
+// stateFilter Returns services that satisfy all predicates.
+func (c *ctl) stateFilter(services []string, predicates []func(s *status) bool) []string {
+	dirs := []string{}
+	for _, dir := range services {
+		status := newStatus(dir, c.serviceName(dir))
+		selected := true
+		for _, predicate := range predicates {
+			selected = selected && predicate(status)
+		}
+		if selected {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// stateCompletions Returns state selectors (with what precedes them) starting with prefix.
+func stateCompletions(prefix string) []string {
+	i := strings.LastIndexByte(prefix, '@')
+	if i == -1 {
+		return nil
+	}
+	compl := []string{}
+	for selector := range stateSelectors {
+		if strings.HasPrefix(selector, prefix[i+1:]) {
+			compl = append(compl, fmt.Sprintf("%s%s ", prefix[:i+1], selector))
+		}
+	}
+	sort.Strings(compl)
+	return compl
+}
@@ -0,0 +1,108 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+// fakeService Creates service directory with supervise/ok and supervise/status
+// as regular files, so that it can be read without runsv.
+func fakeService(dir, name string, status []byte, down bool) {
+	fatal(os.MkdirAll(path.Join(dir, name, "supervise"), 0755))
+	fatal(os.WriteFile(path.Join(dir, name, "supervise/ok"), nil, 0644))
+	fatal(os.WriteFile(path.Join(dir, name, "supervise/status"), status, 0644))
+	if down {
+		fatal(os.WriteFile(path.Join(dir, name, "down"), nil, 0644))
+	}
+}
+
+// fakeStatus Returns sv status string with given values.
+func fakeStatus(secs uint64, pid byte, paused bool, want byte, state byte) []byte {
+	status := make([]byte, 20)
+	for i := 7; i >= 0; i-- {
+		status[i] = byte(secs)
+		secs >>= 8
+	}
+	status[12], status[17], status[19] = pid, want, state
+	if paused {
+		status[16] = 1
+	}
+	return status
+}
+
+func TestStateSelectors(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	fakeService(dir, "web-1", fakeStatus(svNow()-3600, 42, false, 'u', 1), false)
+	fakeService(dir, "web-2", fakeStatus(svNow()-10, 0, false, 'u', 0), false)
+	fakeService(dir, "web-3", fakeStatus(svNow()-10, 0, false, 'd', 0), true)
+	fakeService(dir, "db", fakeStatus(svNow()-10, 43, true, 'u', 1), true)
+	fakeService(dir, "cron", fakeStatus(svNow()-10, 44, false, 'd', 2), false)
+	fatal(os.MkdirAll(path.Join(dir, "broken"), 0755))
+
+	svctl := ctl{basedir: dir, groups: map[string][]string{
+		"web":  {"web-*"},
+		"down": {"db"},
+		"sel":  {"@web@down", "!web-3"},
+	}}
+	defs := []struct {
+		pattern  string
+		services []string
+	}{
+		{"@up", []string{"cron", "db", "web-1"}},
+		{"@down", []string{"web-2", "web-3"}},
+		{"web*@down", []string{"web-2", "web-3"}},
+		{"@paused", []string{"db"}},
+		{"@errored", []string{"broken"}},
+		{"@finishing", []string{"cron"}},
+		{"@want-up-but-down", []string{"web-2"}},
+		{"@normally-down", []string{"db", "web-3"}},
+		{"web*@normally-up@down", []string{"web-2"}},
+		{"@uptime<60s", []string{"cron", "db", "web-2", "web-3"}},
+		{"@uptime>1m", []string{"web-1"}},
+		{"@web@up", []string{"web-1"}},
+		{"@sel", []string{"web-2"}},
+		{"@uptime<soon", []string{}},
+	}
+	for _, def := range defs {
+		services := svctl.Services(def.pattern, false)
+		names := make([]string, len(services))
+		for i, service := range services {
+			names[i] = svctl.serviceName(service)
+		}
+		if !equal(names, def.services) {
+			t.Errorf("ERROR IN SELECTOR: `%v` != `%v` for `%s`", names, def.services, def.pattern)
+		}
+	}
+
+	compl := stateCompletions("web*@d")
+	if !equal(compl, []string{"web*@down "}) {
+		t.Errorf("ERROR IN COMPLETION: `%v`", compl)
+	}
+	if compl := stateCompletions("web"); len(compl) != 0 {
+		t.Errorf("ERROR IN COMPLETION: `%v`", compl)
+	}
+}
@@ -0,0 +1,159 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// seqKinds Maps actions that can be replaced by a sequence to the sequence name.
+var seqKinds = map[string]string{"d": "stop-sequence", "h": "reload-sequence"}
+
+// seqSignals Maps signal names usable in sequences to runsv control bytes.
+var seqSignals = map[string]byte{
+	"STOP": 'p', "CONT": 'c', "HUP": 'h', "ALRM": 'a', "INT": 'i',
+	"QUIT": 'q', "USR1": '1', "USR2": '2', "TERM": 't', "KILL": 'k',
+}
+
+// seqStep Is a single step of a sequence, either a signal or a wait.
+type seqStep struct {
+	signal string
+	wait   time.Duration
+}
+
+// seqParse Parses sequence definition, i.e. whitespace separated signal names
+// (e.g. QUIT) and durations to wait between them (e.g. 5s).
+// Everything after '#' on a line is ignored.
+func seqParse(definition string) ([]seqStep, error) {
+	steps := []seqStep{}
+	signals := 0
+	for _, line := range strings.Split(definition, "\n") {
+		if i := strings.IndexByte(line, '#'); i != -1 {
+			line = line[:i]
+		}
+		for _, field := range strings.Fields(line) {
+			name := strings.TrimPrefix(strings.ToUpper(field), "SIG")
+			if _, ok := seqSignals[name]; ok {
+				steps = append(steps, seqStep{signal: name})
+				signals++
+				continue
+			}
+			wait, err := time.ParseDuration(field)
+			if err != nil || wait < 0 {
+				return nil, fmt.Errorf("%s: unknown signal or duration", field)
+			}
+			steps = append(steps, seqStep{wait: wait})
+		}
+	}
+	if signals == 0 {
+		return nil, fmt.Errorf("no signals")
+	}
+	return steps, nil
+}
+
+// sequence Returns steps replacing action for service, or nil if there are none.
+//
+// Sequences are read from `stop-sequence`/`reload-sequence` entries in config
+// file (see configNames) or, if there are none, from files with the same names in service directory.
+func (c *ctl) sequence(action []byte, service string) ([]seqStep, error) {
+	kind, ok := seqKinds[string(action)]
+	if !ok {
+		return nil, nil
+	}
+	var definition string
+	for _, name := range c.configNames(service) {
+		if definition, ok = c.sequences[kind][name]; ok {
+			break
+		}
+	}
+	if !ok {
+		data, err := os.ReadFile(path.Join(service, kind))
+		if err != nil {
+			return nil, nil
+		}
+		definition = string(data)
+	}
+	steps, err := seqParse(definition)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %s", c.serviceName(service), kind, err)
+	}
+	return steps, nil
+}
+
+// runSequence Sends steps to service instead of action.
+// Returns action to check the final state with.
+//
+// When stopping, runsv is told to not restart the service first,
+// and the remaining steps are skipped once it is down.
+// If the service is still not down after a final wait, it is sent 'd' as well.
+func (c *ctl) runSequence(action []byte, steps []seqStep, service string, start uint64, opts *ctlOpts) ([]byte, error) {
+	name := c.serviceName(service)
+	stop := action[0] == 'd'
+	send := func(action byte, signal string) error {
+		if opts.verbose {
+			c.printf("%s: sending %s\n", name, signal)
+		}
+		return c.control([]byte{action}, service)
+	}
+
+	check := action
+	if stop {
+		if err := send('o', "ONCE"); err != nil {
+			return nil, err
+		}
+	}
+	timedOut := false
+	for _, step := range steps {
+		if step.signal != "" {
+			check = []byte{seqSignals[step.signal]}
+			if err := send(check[0], step.signal); err != nil {
+				return nil, err
+			}
+			timedOut = false
+			continue
+		}
+		if opts.verbose {
+			c.printf("%s: waiting %s\n", name, step.wait)
+		}
+		if !stop {
+			time.Sleep(step.wait)
+			continue
+		}
+		if _, ok := c.waitFor(action, service, start, step.wait); ok {
+			return action, nil
+		}
+		timedOut = true
+	}
+	if !stop {
+		return check, nil
+	}
+	if timedOut {
+		if err := send('d', "DOWN"); err != nil {
+			return nil, err
+		}
+	}
+	return action, nil
+}
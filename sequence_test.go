@@ -0,0 +1,89 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestSeqParse(t *testing.T) {
+	steps, err := seqParse("# java\nUSR2 5s\nSIGTERM # then\n")
+	expected := []seqStep{{signal: "USR2"}, {wait: 5 * time.Second}, {signal: "TERM"}}
+	if err != nil || len(steps) != len(expected) {
+		t.Fatalf("ERROR IN STEPS: `%v` != `%v` (%v)", steps, expected, err)
+	}
+	for i, step := range steps {
+		if step != expected[i] {
+			t.Errorf("ERROR IN STEPS: `%v` != `%v`", step, expected[i])
+		}
+	}
+	for _, definition := range []string{"", "5s", "TERM FOO", "TERM -1s"} {
+		if _, err := seqParse(definition); err == nil {
+			t.Errorf("ERROR IN STEPS: expected error for `%s`", definition)
+		}
+	}
+}
+
+func TestSequence(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"nginx", "db", "broken"} {
+		fatal(os.MkdirAll(path.Join(dir, name), 0755))
+	}
+	fatal(os.WriteFile(path.Join(dir, "nginx", "stop-sequence"), []byte("QUIT\n"), 0644))
+	fatal(os.WriteFile(path.Join(dir, "db", "stop-sequence"), []byte("QUIT\n"), 0644))
+	fatal(os.WriteFile(path.Join(dir, "broken", "reload-sequence"), []byte("NOPE\n"), 0644))
+
+	svctl := ctl{basedir: dir, sequences: map[string]map[string]string{
+		"stop-sequence": {"db": "INT"},
+	}}
+	defs := []struct {
+		action  string
+		service string
+		signal  string
+		err     bool
+	}{
+		{"d", "nginx", "QUIT", false},
+		{"d", "db", "INT", false},
+		{"h", "nginx", "", false},
+		{"t", "nginx", "", false},
+		{"h", "broken", "", true},
+		{"d", "broken", "", false},
+	}
+	for _, def := range defs {
+		steps, err := svctl.sequence([]byte(def.action), path.Join(dir, def.service))
+		if (err != nil) != def.err {
+			t.Errorf("ERROR IN SEQUENCE: unexpected error `%v` for `%s %s`", err, def.action, def.service)
+		}
+		signal := ""
+		if len(steps) > 0 {
+			signal = steps[0].signal
+		}
+		if signal != def.signal {
+			t.Errorf("ERROR IN SEQUENCE: `%s` != `%s` for `%s %s`", signal, def.signal, def.action, def.service)
+		}
+	}
+}
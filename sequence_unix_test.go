@@ -0,0 +1,106 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"path"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeRunsv Reads control bytes sent to service through a fifo,
+// marking the service down once it gets downOn.
+// Returns function returning the bytes read so far, and the one stopping it.
+func fakeRunsv(service string, downOn byte) (func() string, func()) {
+	control := path.Join(service, "supervise/control")
+	fatal(syscall.Mkfifo(control, 0600))
+	// Opened for writing too, so that it does not see EOF between writers.
+	f, err := os.OpenFile(control, os.O_RDWR, 0600)
+	fatal(err)
+
+	var mu sync.Mutex
+	got := []byte{}
+	go func() {
+		b := make([]byte, 1)
+		for {
+			if _, err := f.Read(b); err != nil {
+				return
+			}
+			mu.Lock()
+			got = append(got, b[0])
+			mu.Unlock()
+			if b[0] == downOn {
+				fatal(os.WriteFile(
+					path.Join(service, "supervise/status"),
+					fakeStatus(svNow(), 0, false, 'd', 0), 0644,
+				))
+			}
+		}
+	}()
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return string(got)
+	}, func() { f.Close() }
+}
+
+func TestRunSequence(t *testing.T) {
+	defs := []struct {
+		action   string
+		sequence string
+		downOn   byte
+		expected string
+	}{
+		{"d", "TERM 300ms INT 300ms QUIT", 'i', "oti"},
+		{"d", "TERM 200ms", 'd', "otd"},
+		{"d", "TERM 200ms INT", 'd', "oti"},
+		{"d", "QUIT", 'q', "oq"},
+		{"h", "USR2 100ms HUP", 0, "2h"},
+	}
+	for _, def := range defs {
+		dir, err := os.MkdirTemp("", "svctl_tests")
+		fatal(err)
+		fakeService(dir, "app", fakeStatus(svNow()-10, 42, false, 'u', 1), false)
+		service := path.Join(dir, "app")
+		sent, stop := fakeRunsv(service, def.downOn)
+
+		svctl := ctl{basedir: dir}
+		steps, err := seqParse(def.sequence)
+		fatal(err)
+		if _, err := svctl.runSequence([]byte(def.action), steps, service, svNow(), &ctlOpts{}); err != nil {
+			t.Errorf("ERROR IN RUN SEQUENCE: unexpected error `%v` for `%s`", err, def.sequence)
+		}
+		for deadline := time.Now().Add(time.Second); len(sent()) < len(def.expected) && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if sent() != def.expected {
+			t.Errorf("ERROR IN RUN SEQUENCE: `%s` != `%s` for `%s`", sent(), def.expected, def.sequence)
+		}
+		stop()
+		os.RemoveAll(dir)
+	}
+}
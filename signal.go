@@ -0,0 +1,153 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// sigParse Parses signal name (with or without SIG prefix, e.g. WINCH, SIGUSR1,
+// RTMIN+2) or number.
+func sigParse(name string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(name); err == nil && n > 0 {
+		return syscall.Signal(n), nil
+	}
+	upper := strings.TrimPrefix(strings.ToUpper(name), "SIG")
+	if sig, ok := sigNames[upper]; ok {
+		return sig, nil
+	}
+	if sigRtMin != 0 {
+		for _, rt := range []struct {
+			prefix string
+			base   int
+			sign   int
+		}{{"RTMIN", sigRtMin, 1}, {"RTMAX", sigRtMax, -1}} {
+			if !strings.HasPrefix(upper, rt.prefix) {
+				continue
+			}
+			offset := 0
+			if rest := upper[len(rt.prefix):]; rest != "" {
+				n, err := strconv.Atoi(rest)
+				if err != nil || n*rt.sign < 0 {
+					break
+				}
+				offset = n
+			}
+			if sig := rt.base + offset; sig >= sigRtMin && sig <= sigRtMax {
+				return syscall.Signal(sig), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%s: unknown signal", name)
+}
+
+// procParents Returns parent pid of every process found in /proc.
+func procParents() (map[int]int, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read /proc")
+	}
+	parents := map[int]int{}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path.Join("/proc", entry.Name(), "stat"))
+		if err != nil {
+			// Process is already gone.
+			continue
+		}
+		// Command name can contain anything, so look after its closing paren.
+		// What follows is " STATE PPID ...".
+		stat := string(data)
+		fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+		if len(fields) < 2 {
+			continue
+		}
+		if ppid, err := strconv.Atoi(fields[1]); err == nil {
+			parents[pid] = ppid
+		}
+	}
+	return parents, nil
+}
+
+// procTree Returns pid along with all its descendants, parents before children.
+func procTree(pid int, parents map[int]int) []int {
+	children := map[int][]int{}
+	for child, parent := range parents {
+		children[parent] = append(children[parent], child)
+	}
+	tree := []int{pid}
+	for i := 0; i < len(tree); i++ {
+		tree = append(tree, children[tree[i]]...)
+	}
+	return tree
+}
+
+// Signal Sends sig directly to the process of each of services
+// and, if tree is true, to all its descendants.
+func (c *ctl) Signal(name string, sig syscall.Signal, services []string, tree bool) {
+	var parents map[int]int
+	if tree {
+		var err error
+		if parents, err = procParents(); err != nil {
+			c.fail()
+			c.println(err)
+			return
+		}
+	}
+	for _, service := range services {
+		status := newStatus(service, c.serviceName(service))
+		if status.Errored() {
+			c.fail()
+			c.println(status)
+			continue
+		}
+		if status.sv.Pid == 0 {
+			c.fail()
+			c.printf("%s: not running\n", status.name)
+			continue
+		}
+		pids := []int{int(status.sv.Pid)}
+		if tree {
+			pids = procTree(pids[0], parents)
+		}
+		sent := []string{}
+		for _, pid := range pids {
+			if err := sigKill(pid, sig); err != nil {
+				c.fail()
+				c.printf("%s: unable to send %s to %d: %s\n", status.name, name, pid, err)
+				continue
+			}
+			sent = append(sent, strconv.Itoa(pid))
+		}
+		if len(sent) > 0 {
+			c.printf("%s: sent %s to %s\n", status.name, name, strings.Join(sent, ", "))
+		}
+	}
+}
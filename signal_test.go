@@ -0,0 +1,76 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestSigParse(t *testing.T) {
+	defs := []struct {
+		name string
+		sig  syscall.Signal
+	}{
+		{"TERM", syscall.SIGTERM},
+		{"sigusr1", syscall.SIGUSR1},
+		{"WINCH", syscall.SIGWINCH},
+		{"9", syscall.SIGKILL},
+		{"RTMIN", syscall.Signal(sigRtMin)},
+		{"RTMIN+2", syscall.Signal(sigRtMin + 2)},
+		{"SIGRTMAX-1", syscall.Signal(sigRtMax - 1)},
+	}
+	for _, def := range defs {
+		if sigRtMin == 0 && strings.Contains(def.name, "RT") {
+			continue
+		}
+		sig, err := sigParse(def.name)
+		if err != nil || sig != def.sig {
+			t.Errorf("ERROR IN SIGNAL: `%d` != `%d` for `%s` (%v)", sig, def.sig, def.name, err)
+		}
+	}
+	for _, name := range []string{"", "NOPE", "0", "-1", "RTMIN-1", "RTMIN+99", "RTMINX"} {
+		if _, err := sigParse(name); err == nil {
+			t.Errorf("ERROR IN SIGNAL: expected error for `%s`", name)
+		}
+	}
+}
+
+func TestProcTree(t *testing.T) {
+	parents := map[int]int{1: 0, 10: 1, 11: 10, 12: 10, 13: 11, 20: 1}
+	tree := procTree(10, parents)
+	if len(tree) != 4 || tree[0] != 10 || tree[3] != 13 {
+		t.Errorf("ERROR IN TREE: `%v`", tree)
+	}
+
+	parents, err := procParents()
+	if err != nil {
+		t.Skip(err)
+	}
+	if parents[os.Getpid()] != os.Getppid() {
+		t.Errorf("ERROR IN PARENTS: `%d` != `%d`", parents[os.Getpid()], os.Getppid())
+	}
+}
@@ -21,7 +21,16 @@
 
 package main
 
-import "time"
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // svPid Parses process PID from sv status string.
 // Returns `0` if process is not running.
@@ -36,12 +45,97 @@ func svPid(status []byte) uint {
 	return pid
 }
 
-// svStatus Parses process state from sv status string.
-func svStatus(status []byte, pid uint) string {
-	if pid != 0 && status[16] != 0 {
+// svState Represents fully parsed sv status string,
+// along with whether the service is normally up.
+type svState struct {
+	Time       uint64 // TAI64 seconds of the last start/stop.
+	Nano       uint32 // Nanoseconds of the last start/stop.
+	Pid        uint   // Process PID, 0 if process is not running.
+	Paused     bool   // Whether process got STOP.
+	Want       byte   // Either 'u', 'd' or 0, if runsv does not want anything.
+	Term       bool   // Whether process got TERM.
+	State      byte   // 0 when down, 1 when running and 2 when finishing.
+	NormallyUp bool   // Whether there is no `down` file.
+}
+
+// svParse Parses sv status string.
+// normallyUp tells whether there is no `down` file in service directory.
+func svParse(status []byte, normallyUp bool) *svState {
+	return &svState{
+		Time:       svTime(status),
+		Nano:       svNano(status),
+		Pid:        svPid(status),
+		Paused:     status[16] != 0,
+		Want:       status[17],
+		Term:       status[18] != 0,
+		State:      status[19],
+		NormallyUp: normallyUp,
+	}
+}
+
+// svReadOnly Reads state from supervise/stat and supervise/pid, which,
+// unlike supervise/status, are readable by unprivileged users.
+//
+// runsv writes state there, followed by ", paused", ", got TERM" and
+// ", want down" or ", want exit", as applicable. It does not tell whether
+// a stopped process wants up, and time of the last start/stop is only
+// approximated with modification time of supervise/stat.
+func svReadOnly(dir string, normallyUp bool) (*svState, error) {
+	fn := path.Join(dir, "supervise/stat")
+	stat, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read supervise/stat")
+	}
+	fi, err := os.Stat(fn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read supervise/stat")
+	}
+	state := &svState{
+		Time:       uint64(svTimeMod + fi.ModTime().Unix()),
+		Nano:       uint32(fi.ModTime().Nanosecond()),
+		NormallyUp: normallyUp,
+	}
+	for i, field := range strings.Split(strings.TrimSpace(string(stat)), ", ") {
+		switch {
+		case i == 0 && field == "down":
+			state.State = 0
+		case i == 0 && field == "run":
+			state.State = 1
+		case i == 0 && field == "finish":
+			state.State = 2
+		case i == 0:
+			return nil, fmt.Errorf("unable to read supervise/stat: wrong format")
+		case field == "paused":
+			state.Paused = true
+		case field == "got TERM":
+			state.Term = true
+		case field == "want down":
+			state.Want = 'd'
+		case field == "want exit":
+			state.Want = 'x'
+		}
+	}
+
+	pid, err := os.ReadFile(path.Join(dir, "supervise/pid"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read supervise/pid")
+	}
+	if pid := strings.TrimSpace(string(pid)); pid != "" {
+		n, err := strconv.ParseUint(pid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read supervise/pid: wrong format")
+		}
+		state.Pid = uint(n)
+	}
+	return state, nil
+}
+
+// Status Returns process state name.
+func (s *svState) Status() string {
+	if s.Pid != 0 && s.Paused {
 		return "PAUSED"
 	}
-	switch status[19] {
+	switch s.State {
 	case 0:
 		return "STOPPED"
 	case 1:
@@ -53,42 +147,89 @@ func svStatus(status []byte, pid uint) string {
 	}
 }
 
+// Uptime Returns time since the last start/stop.
+func (s *svState) Uptime() time.Duration {
+	return time.Since(time.Unix(int64(s.Time-svTimeMod), int64(s.Nano)))
+}
+
+// Flags Returns additional information about the state,
+// shown in the same circumstances as by `sv status`.
+// Being paused is not among them, as it is shown as the state already, see Status.
+func (s *svState) Flags() []string {
+	flags := []string{}
+	if s.Pid != 0 && !s.NormallyUp {
+		flags = append(flags, "normally down")
+	}
+	if s.Pid == 0 && s.NormallyUp {
+		flags = append(flags, "normally up")
+	}
+	if s.Pid == 0 && s.Want == 'u' {
+		flags = append(flags, "want up")
+	}
+	if s.Pid != 0 && s.Want == 'd' {
+		flags = append(flags, "want down")
+	}
+	if s.Pid != 0 && s.Term {
+		flags = append(flags, "got TERM")
+	}
+	return flags
+}
+
+// svCheckScript Runs service's check script, if there is one.
+// Returns whether it succeeded and whether it exists at all.
+func svCheckScript(dir string) (bool, bool) {
+	fi, err := os.Stat(path.Join(dir, "check"))
+	if err != nil || fi.IsDir() || fi.Mode()&0111 == 0 {
+		return true, false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), svCheckTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "./check")
+	cmd.Dir = dir
+	return cmd.Run() == nil, true
+}
+
+// svCheckActions Are actions that wait for service to be up
+// and so for its check script to succeed, like `sv` does.
+const svCheckActions = "utkha12"
+
+// svCheckTimeout Is how long a single check script run can take.
+const svCheckTimeout = 5 * time.Second
+
 // svCheck Checks whether process already entered desired state
 // after sending it the control action.
-func svCheck(action, status []byte, start uint64) bool {
+// ready tells whether service's check script succeeded (or there is none).
+func svCheck(action []byte, state *svState, start uint64, ready bool) bool {
 	for _, a := range action {
-		pid := svPid(status)
+		pid := state.Pid
 		switch a {
 		case 'x':
 			//TODO
 		case 'u':
-			if pid == 0 || status[19] != 1 {
+			if pid == 0 || state.State != 1 || !ready {
 				return false
 			}
-			//TODO: !checkscript():return false
 		case 'd':
-			if pid != 0 || status[19] != 0 {
+			if pid != 0 || state.State != 0 {
 				return false
 			}
 		case 't', 'k', 'h', 'a', '1', '2':
-			if pid == 0 && status[17] == 'd' {
+			if pid == 0 && state.Want == 'd' {
 				break
 			}
-			time := svTime(status)
-			if start > time || pid == 0 || status[18] != 0 { //TODO: ||!checkscript()
+			if start > state.Time || pid == 0 || state.Term || !ready {
 				return false
 			}
 		case 'o':
-			time := svTime(status)
-			if (pid == 0 && start > time) || (pid != 0 && status[17] != 'd') {
+			if (pid == 0 && start > state.Time) || (pid != 0 && state.Want != 'd') {
 				return false
 			}
 		case 'p':
-			if pid != 0 && status[16] == 0 {
+			if pid != 0 && !state.Paused {
 				return false
 			}
 		case 'c':
-			if pid != 0 && status[16] != 0 {
+			if pid != 0 && state.Paused {
 				return false
 			}
 		}
@@ -98,8 +239,8 @@ func svCheck(action, status []byte, start uint64) bool {
 
 // svCheckControl Checks whether we should send a control action.
 // We should not when process recently got ONCE or TERM.
-func svCheckControl(action, status []byte) bool {
-	return status[17] != action[0] || (action[0] == 'd' && status[18] != 1)
+func svCheckControl(action []byte, state *svState) bool {
+	return state.Want != action[0] || (action[0] == 'd' && !state.Term)
 }
 
 // svTimeMod Is a time shift constant used by sv (copied from sv sources).
@@ -126,6 +267,18 @@ func svTime(status []byte) uint64 {
 	return time
 }
 
+// svNano Parses nanoseconds part of time from sv status string.
+func svNano(status []byte) uint32 {
+	nano := uint32(status[8])
+	nano <<= 8
+	nano += uint32(status[9])
+	nano <<= 8
+	nano += uint32(status[10])
+	nano <<= 8
+	nano += uint32(status[11])
+	return nano
+}
+
 // svNow Returns current time shifted with sv constant.
 func svNow() uint64 {
 	return uint64(svTimeMod + time.Now().Unix())
@@ -0,0 +1,142 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestSvCheckScript(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+
+	if ok, exists := svCheckScript(dir); !ok || exists {
+		t.Errorf("ERROR IN CHECK: `%t`, `%t` != `true`, `false` for no script", ok, exists)
+	}
+	fatal(os.WriteFile(path.Join(dir, "check"), []byte("#!/bin/sh\ntest -e ready\n"), 0755))
+	if ok, exists := svCheckScript(dir); ok || !exists {
+		t.Errorf("ERROR IN CHECK: `%t`, `%t` != `false`, `true` for failing script", ok, exists)
+	}
+	fatal(os.WriteFile(path.Join(dir, "ready"), nil, 0644))
+	if ok, exists := svCheckScript(dir); !ok || !exists {
+		t.Errorf("ERROR IN CHECK: `%t`, `%t` != `true`, `true` for succeeding script", ok, exists)
+	}
+
+	status := &svState{Pid: 42, State: 1}
+	for _, action := range [][]byte{[]byte("u"), []byte("tcu")} {
+		if !svCheck(action, status, 0, true) {
+			t.Errorf("ERROR IN SVCHECK: should succeed for `%s` when ready", action)
+		}
+		if svCheck(action, status, 0, false) {
+			t.Errorf("ERROR IN SVCHECK: should not succeed for `%s` when not ready", action)
+		}
+	}
+}
+
+func TestSvParse(t *testing.T) {
+	status := make([]byte, 20)
+	copy(status, []byte{0x40, 0, 0, 0, 0x12, 0x34, 0x56, 0x78, 0, 0, 0x01, 0x02, 42, 1, 0, 0, 1, 'd', 1, 1})
+	state := svParse(status, false)
+	expected := svState{
+		Time: 0x4000000012345678, Nano: 0x0102, Pid: 298,
+		Paused: true, Want: 'd', Term: true, State: 1,
+	}
+	if *state != expected {
+		t.Errorf("ERROR IN PARSE: `%+v` != `%+v`", *state, expected)
+	}
+	if state.Status() != "PAUSED" {
+		t.Errorf("ERROR IN STATUS: `%s` != `PAUSED`", state.Status())
+	}
+	flags := []string{"normally down", "want down", "got TERM"}
+	if !equal(state.Flags(), flags) {
+		t.Errorf("ERROR IN FLAGS: `%v` != `%v`", state.Flags(), flags)
+	}
+
+	state = &svState{Want: 'u', NormallyUp: true}
+	flags = []string{"normally up", "want up"}
+	if state.Status() != "STOPPED" || !equal(state.Flags(), flags) {
+		t.Errorf("ERROR IN FLAGS: `%s` `%v` != `STOPPED` `%v`", state.Status(), state.Flags(), flags)
+	}
+}
+
+func TestStatusUptime(t *testing.T) {
+	defs := map[time.Duration]string{
+		250 * time.Millisecond:  "0.25s",
+		1500 * time.Millisecond: "1s",
+		90 * time.Second:        "90s",
+	}
+	for uptime, expected := range defs {
+		if s := statusUptime(uptime); s != expected {
+			t.Errorf("ERROR IN UPTIME: `%s` != `%s` for `%s`", s, expected, uptime)
+		}
+	}
+}
+
+func TestSvReadOnly(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	fatal(os.MkdirAll(path.Join(dir, "supervise"), 0755))
+
+	defs := []struct {
+		stat   string
+		pid    string
+		status string
+		flags  []string
+		err    bool
+	}{
+		{"run\n", "42\n", "RUNNING", []string{}, false},
+		{"run, paused, want down\n", "42\n", "PAUSED", []string{"normally down", "want down"}, false},
+		{"run, got TERM, want down\n", "42\n", "RUNNING", []string{"want down", "got TERM"}, false},
+		{"run, want exit\n", "42\n", "RUNNING", []string{}, false},
+		{"down\n", "\n", "STOPPED", []string{"normally up"}, false},
+		{"finish\n", "42\n", "FINISHING", []string{}, false},
+		{"nope\n", "42\n", "", nil, true},
+		{"run\n", "x\n", "", nil, true},
+	}
+	for _, def := range defs {
+		fatal(os.WriteFile(path.Join(dir, "supervise/stat"), []byte(def.stat), 0644))
+		fatal(os.WriteFile(path.Join(dir, "supervise/pid"), []byte(def.pid), 0644))
+		state, err := svReadOnly(dir, def.status != "PAUSED")
+		if (err != nil) != def.err {
+			t.Errorf("ERROR IN READONLY: unexpected error `%v` for `%q`", err, def.stat)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if state.Status() != def.status || !equal(state.Flags(), def.flags) {
+			t.Errorf("ERROR IN READONLY: `%s` `%v` != `%s` `%v` for `%q`", state.Status(), state.Flags(), def.status, def.flags, def.stat)
+		}
+		if state.Uptime() > time.Minute {
+			t.Errorf("ERROR IN READONLY: uptime `%s` is not based on modification time", state.Uptime())
+		}
+	}
+	os.Remove(path.Join(dir, "supervise/pid"))
+	if _, err := svReadOnly(dir, true); err == nil {
+		t.Errorf("ERROR IN READONLY: expected error for missing supervise/pid")
+	}
+}
@@ -35,8 +35,9 @@ func (c *cmdUp) Action() []byte {
 
 func (c *cmdUp) Help() string {
 	return strings.TrimSpace(`
-up NAMES...   Starts service(s) with matching NAMES.
-              NAMES support globing with '*' and '?'.
+up [OPTIONS] NAMES...   Starts service(s) with matching NAMES.
+                        NAMES support globing with '*' and '?'.
+                        If service has a check script, waits for it to succeed.
 	`)
 }
 
@@ -53,8 +54,8 @@ func (c *cmdDown) Action() []byte {
 
 func (c *cmdDown) Help() string {
 	return strings.TrimSpace(`
-down NAMES...   Stops service(s) with matching NAMES.
-                NAMES support globing with '*' and '?'.
+down [OPTIONS] NAMES...   Stops service(s) with matching NAMES.
+                          NAMES support globing with '*' and '?'.
 	`)
 }
 
@@ -71,10 +72,11 @@ func (c *cmdRestart) Action() []byte {
 
 func (c *cmdRestart) Help() string {
 	return strings.TrimSpace(`
-restart NAMES...   Restarts service(s) with matching NAMES.
-                   NAMES support globing with '*' and '?'.
-                   Waits up to 7 seconds for the service to get back up, then
-                   reports TIMEOUT.
+restart [OPTIONS] NAMES...   Restarts service(s) with matching NAMES.
+                             NAMES support globing with '*' and '?'.
+                             Waits up to 7 seconds (see -w) for the service to get back up
+                             (and its check script, if any, to succeed), then
+                             reports TIMEOUT (or CHECK FAILED).
 	`)
 }
 
@@ -91,8 +93,8 @@ func (c *cmdOnce) Action() []byte {
 
 func (c *cmdOnce) Help() string {
 	return strings.TrimSpace(`
-once NAMES...   Starts service once and does not try to restart it if it stops.
-                NAMES support globing with '*' and '?'.
+once [OPTIONS] NAMES...   Starts service once and does not try to restart it if it stops.
+                          NAMES support globing with '*' and '?'.
 	`)
 }
 
@@ -122,8 +124,8 @@ func (c *cmdSignal) Help() string {
 		'q': "QUIT", '1': "USR1", '2': "USR2", 't': "TERM", 'k': "KILL",
 	}
 	return fmt.Sprintf(strings.TrimSpace(`
-%s NAMES...   Sends signal '%s' to service(s) with matching NAMES.
-%-[3]*s            NAMES support globing with '*' and '?'.
+%s [OPTIONS] NAMES...   Sends signal '%s' to service(s) with matching NAMES.
+%-[3]*s                      NAMES support globing with '*' and '?'.
 	`), c.action, m[c.action[0]], len(c.action), "")
 }
 
@@ -24,6 +24,7 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -31,11 +32,11 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/adrg/xdg"
 	"github.com/peterh/liner"
 )
 
@@ -48,10 +49,13 @@ type status struct {
 
 	Offsets []int
 
-	sv       []byte
+	sv       *svState
 	svStatus string
-	svPid    uint
-	svTime   uint64
+	svReady  bool
+	readOnly bool
+
+	// log Is the status of service's log service, when shown inline.
+	log *status
 }
 
 // newStatus Creates new status representation for given directory and name.
@@ -59,31 +63,42 @@ func newStatus(dir, name string) *status {
 	s := &status{Offsets: make([]int, 2), name: name}
 	s.Offsets[0] = len(s.name)
 
-	status, err := s.status(dir)
+	sv, err := s.status(dir)
 	if err != nil {
 		s.err = err
 
 		s.Offsets[1] = len("ERROR")
 	} else {
-		s.svPid = svPid(status)
-		s.svStatus = svStatus(status, s.svPid)
-		s.svTime = svTime(status)
+		s.sv = sv
+		s.svStatus = s.sv.Status()
+		s.svReady = true
 
 		s.Offsets[1] = len(s.svStatus)
-		if s.svStatus == "RUNNING" {
-			s.Offsets[1] += len(fmt.Sprintf(" (pid %d)", s.svPid))
+		if s.hasPid() {
+			s.Offsets[1] += len(fmt.Sprintf(" (pid %d)", s.sv.Pid))
 		}
 	}
-	s.sv = status
 
 	return s
 }
 
 // status Reads current status from specified dir.
-func (s *status) status(dir string) ([]byte, error) {
-	if _, err := os.OpenFile(path.Join(dir, "supervise/ok"), os.O_WRONLY, 0600); err != nil {
+//
+// If we are not allowed to control the service, falls back to
+// the world-readable supervise/stat and supervise/pid, see svReadOnly.
+func (s *status) status(dir string) (*svState, error) {
+	_, err := os.Stat(path.Join(dir, "down"))
+	normallyUp := os.IsNotExist(err)
+
+	ok, err := os.OpenFile(path.Join(dir, "supervise/ok"), os.O_WRONLY, 0600)
+	if os.IsPermission(err) {
+		s.readOnly = true
+		return svReadOnly(dir, normallyUp)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("unable to open supervise/ok")
 	}
+	ok.Close()
 
 	fstatus, err := os.Open(path.Join(dir, "supervise/status"))
 	if err != nil {
@@ -99,7 +114,12 @@ func (s *status) status(dir string) ([]byte, error) {
 		}
 		return nil, fmt.Errorf("unable to read supervise/status")
 	}
-	return b, nil
+	return svParse(b, normallyUp), nil
+}
+
+// hasPid Returns whether pid should be displayed along the status.
+func (s *status) hasPid() bool {
+	return s.svStatus == "RUNNING" || s.svStatus == "STARTING"
 }
 
 // Check Performs svCheck on status, if retrieved successfully.
@@ -107,7 +127,28 @@ func (s *status) Check(action []byte, start uint64) bool {
 	if s.err != nil {
 		return true
 	}
-	return svCheck(action, s.sv, start)
+	return svCheck(action, s.sv, start, s.svReady)
+}
+
+// checkScript Runs service's check script in dir, if status satisfies action
+// otherwise and action waits for service to be up, see svCheckActions.
+// Until the script succeeds, status is STARTING.
+func (s *status) checkScript(dir string, action []byte, start uint64) {
+	if s.err != nil || s.svStatus != "RUNNING" || !bytes.ContainsAny(action, svCheckActions) {
+		return
+	}
+	if !svCheck(action, s.sv, start, true) {
+		return
+	}
+	if ok, exists := svCheckScript(dir); exists && !ok {
+		s.svReady = false
+		s.svStatus = "STARTING"
+	}
+}
+
+// Starting Returns whether service is running, but its check script does not succeed yet.
+func (s *status) Starting() bool {
+	return s.err == nil && !s.svReady
 }
 
 // CheckControl Performs svCheckControl on status.
@@ -127,43 +168,166 @@ func (s *status) String() string {
 		return status.String()
 	}
 	fmt.Fprintf(&status, s.svStatus)
-	if s.svStatus == "RUNNING" {
-		fmt.Fprintf(&status, " (pid %d)", s.svPid)
+	if s.hasPid() {
+		fmt.Fprintf(&status, " (pid %d)", s.sv.Pid)
 	}
 	fmt.Fprintf(
-		&status, "%-[1]*s%ds",
-		s.Offsets[1]+3-status.Len()+s.Offsets[0]+3, "", svNow()-s.svTime,
+		&status, "%-[1]*s%s",
+		s.Offsets[1]+3-status.Len()+s.Offsets[0]+3, "", statusUptime(s.sv.Uptime()),
 	)
+	for _, flag := range s.sv.Flags() {
+		fmt.Fprintf(&status, ", %s", flag)
+	}
+	if s.readOnly {
+		fmt.Fprintf(&status, ", read-only")
+	}
+	if s.log != nil {
+		fmt.Fprintf(&status, "; log: %s", s.log.short())
+	}
 	return status.String()
 }
 
+// short Returns stringified version of the status without name and alignment.
+func (s *status) short() string {
+	if s.err != nil {
+		return fmt.Sprintf("ERROR %s", s.err)
+	}
+	var status bytes.Buffer
+	fmt.Fprintf(&status, s.svStatus)
+	if s.hasPid() {
+		fmt.Fprintf(&status, " (pid %d)", s.sv.Pid)
+	}
+	fmt.Fprintf(&status, " %s", statusUptime(s.sv.Uptime()))
+	for _, flag := range s.sv.Flags() {
+		fmt.Fprintf(&status, ", %s", flag)
+	}
+	if s.readOnly {
+		fmt.Fprintf(&status, ", read-only")
+	}
+	return status.String()
+}
+
+// logLost Returns whether service is running, but its log service is not,
+// i.e. its output is being lost.
+func (s *status) logLost() bool {
+	return s.log != nil && s.hasPid() && !s.log.hasPid()
+}
+
+// statusUptime Formats uptime as whole seconds,
+// or with sub-second precision if it is shorter than a second.
+func statusUptime(uptime time.Duration) string {
+	if uptime < time.Second {
+		return fmt.Sprintf("%.2fs", uptime.Seconds())
+	}
+	return fmt.Sprintf("%ds", uptime/time.Second)
+}
+
+// themeKey Returns key used to color the status, see themeKeys.
+func (s *status) themeKey() string {
+	if s.err != nil {
+		return "error"
+	}
+	if s.logLost() {
+		return "loglost"
+	}
+	return strings.ToLower(s.svStatus)
+}
+
 // Errored Returns whether status retrieval ended with error or not.
 func (s *status) Errored() bool {
 	return s.err != nil
 }
 
+// statusRecord Represents status in a form suitable for machine-readable output.
+type statusRecord struct {
+	Name     string `json:"name"`
+	State    string `json:"state"`
+	Pid      uint   `json:"pid"`
+	Uptime   uint64 `json:"uptime"`
+	Paused   bool   `json:"paused"`
+	Want     string `json:"want"`
+	Term     bool   `json:"term"`
+	Normally string `json:"normally"`
+	ReadOnly bool   `json:"read_only"`
+	Error    string `json:"error"`
+}
+
+// Record Returns machine-readable representation of the status.
+func (s *status) Record() *statusRecord {
+	r := &statusRecord{Name: s.name}
+	if s.err != nil {
+		r.State = "ERROR"
+		r.Error = s.err.Error()
+		return r
+	}
+	r.State = s.svStatus
+	r.Pid = s.sv.Pid
+	r.Uptime = uint64(s.sv.Uptime() / time.Second)
+	r.Paused = s.sv.Paused
+	switch s.sv.Want {
+	case 'u':
+		r.Want = "up"
+	case 'd':
+		r.Want = "down"
+	}
+	r.Term = s.sv.Term
+	r.Normally = "down"
+	if s.sv.NormallyUp {
+		r.Normally = "up"
+	}
+	r.ReadOnly = s.readOnly
+	return r
+}
+
 // ctl Represents main svctl entry point.
 type ctl struct {
-	line    *liner.State
-	basedir string
-	stdout  io.Writer
+	line       *liner.State
+	basedir    string
+	roots      []svRoot
+	startRoots []svRoot
+	bookmarks  map[string]string
+	selection  []string
+	srcdir     string
+	stdout     io.Writer
+	colors     bool
+	format     string
+	wait       time.Duration
+	prompt     string
+	view       string
+	theme      map[string]string
+	inlineLog  bool
+	aliases    map[string]string
+	groups     map[string][]string
+	deps       map[string][]string
+	sequences  map[string]map[string]string
+	sources    map[string]string
+
+	mu     sync.Mutex
+	failed bool
 }
 
 // newCtl Creates new ctl instance.
-// Initializes input prompt, reads history, reads $SVDIR.
-func newCtl(stdout io.Writer) *ctl {
-	c := &ctl{line: liner.NewLiner(), stdout: stdout}
-
-	fn, _ := xdg.DataFile("svctl/hist")
-	if f, err := os.Open(fn); err == nil {
-		c.line.ReadHistory(f)
-		f.Close()
+// Reads configuration file and environment and, if interactive,
+// initializes input prompt and reads history.
+func newCtl(stdout io.Writer, interactive bool) *ctl {
+	c := &ctl{stdout: stdout}
+	c.configDefaults()
+	c.loadConfig()
+	c.loadEnv()
+	c.loadBookmarks()
+	c.startRoots = c.Roots()
+	if f, ok := stdout.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			c.colors = true
+		}
 	}
-	c.basedir = os.Getenv("SVDIR")
-	if c.basedir == "" {
-		c.basedir = "/service"
+	if !interactive {
+		return c
 	}
 
+	c.line = liner.NewLiner()
+	c.readHistory(true)
+
 	c.line.SetTabCompletionStyle(liner.TabPrints)
 	c.line.SetWordCompleter(c.completer)
 
@@ -172,17 +336,11 @@ func newCtl(stdout io.Writer) *ctl {
 
 // Close Closes input prompt, saves history to file.
 func (c *ctl) Close() {
-	fn, _ := xdg.DataFile("svctl/hist")
-	f, err := os.Create(fn)
-	if err != nil {
-		log.Printf("error opening history file: %s\n", err)
+	if c.line == nil {
 		return
 	}
-	if n, err := c.line.WriteHistory(f); err != nil {
-		log.Printf("error writing history file: %s, lines written: %d\n", err, n)
-	}
+	c.writeHistory()
 	c.line.Close()
-	f.Close()
 }
 
 func (c *ctl) completer(line string, pos int) (h string, compl []string, t string) {
@@ -192,15 +350,16 @@ func (c *ctl) completer(line string, pos int) (h string, compl []string, t strin
 	}
 	i := strings.Count(line[:pos], " ")
 
+	cmd := cmdMatch(s[0])
 	if s[0] == "?" || s[0] == "help" {
 		compl = cmdMatchName(s[i])
+	} else if strings.HasPrefix(s[i], "-") && len(cmdOptionsOf(cmd)) > 0 {
+		// Commands without options may give '-' a meaning of their own, e.g. select.
+		compl = cmdMatchOption(cmd, s[i])
+	} else if completer, ok := cmd.(cmdCompleter); ok {
+		compl = completer.Complete(c, s[i])
 	} else {
-		services := c.Services(fmt.Sprintf("%s*", s[i]), true)
-
-		compl = make([]string, len(services))
-		for i, service := range services {
-			compl[i] = fmt.Sprintf("%s ", c.serviceName(service))
-		}
+		compl = c.nameCompletions(s[i])
 	}
 	h = fmt.Sprintf("%s ", strings.Join(s[:i], " "))
 	t = strings.Join(s[i+1:], " ")
@@ -218,8 +377,96 @@ func (c *ctl) println(a ...interface{}) {
 	fmt.Fprintln(c.stdout, a...)
 }
 
-// serviceName Returns name of the service, i.e. directory chain relative to current base.
+// svWait Is the default time to wait for service to reach desired state.
+const svWait = 7 * time.Second
+
+// ctlOpts Holds options of a single command sent to runsv, see cmdOptions.
+type ctlOpts struct {
+	wait     time.Duration
+	noWait   bool
+	verbose  bool
+	parallel int
+	withLog  bool
+	logOnly  bool
+
+	// extra Holds values of command specific options, see parseOpts.
+	extra map[string]string
+}
+
+// parseOpts Separates cmdOptions, along with extra options, from the rest of params.
+func (c *ctl) parseOpts(params []string, extra ...cmdOption) (*ctlOpts, []string, error) {
+	parsed, rest, err := cmdParse(params, append(extra, cmdOptions...))
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := &ctlOpts{wait: c.wait, extra: map[string]string{}}
+	if wait, ok := parsed["-w"]; ok {
+		if opts.wait, err = cmdWait(wait); err != nil {
+			return nil, nil, err
+		}
+	}
+	_, opts.noWait = parsed["--no-wait"]
+	_, opts.verbose = parsed["-v"]
+	_, opts.withLog = parsed["--with-log"]
+	_, opts.logOnly = parsed["--log-only"]
+	if opts.withLog && opts.logOnly {
+		return nil, nil, fmt.Errorf("--with-log and --log-only cannot be used together")
+	}
+	if parallel, ok := parsed["--parallel"]; ok {
+		if opts.parallel, err = strconv.Atoi(parallel); err != nil || opts.parallel < 1 {
+			return nil, nil, fmt.Errorf("%s: invalid number of services", parallel)
+		}
+	}
+	for _, option := range extra {
+		if value, ok := parsed[option.name]; ok {
+			opts.extra[option.name] = value
+		}
+	}
+	return opts, rest, nil
+}
+
+// each Calls fn for all services asynchronically, but at most n at once
+// (or without a limit, if n is 0), and waits for all of them to finish.
+func each(services []string, n int, fn func(service string)) {
+	if n == 0 {
+		n = len(services)
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, n)
+	wg.Add(len(services))
+	for _, service := range services {
+		sem <- struct{}{}
+		go func(service string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(service)
+		}(service)
+	}
+	wg.Wait()
+}
+
+// fail Marks that at least one action did not succeed.
+func (c *ctl) fail() {
+	c.mu.Lock()
+	c.failed = true
+	c.mu.Unlock()
+}
+
+// Failed Returns whether any action failed since ctl was created.
+func (c *ctl) Failed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failed
+}
+
+// serviceName Returns name of the service, i.e. directory chain relative to current base,
+// prefixed with its label when there are more services directories, e.g. `sys:nginx`.
 func (c *ctl) serviceName(dir string) string {
+	if root, name, ok := c.rootName(dir); ok && root.label != "" {
+		return fmt.Sprintf("%s:%s", root.label, name)
+	}
 	if name, err := filepath.Rel(c.basedir, dir); err == nil {
 		return name
 	}
@@ -227,22 +474,61 @@ func (c *ctl) serviceName(dir string) string {
 }
 
 // Services Returns paths to all services matching pattern.
+// Pattern starting with '@' refers to a group, see groupServices.
+// Pattern ending with '@' and state selector refers to services in that state,
+// see stateSelector. Pattern starting with 're:' is a regular expression
+// matched against service names and braces are expanded, see braceExpand.
+// Pattern containing '**' matches services at any depth, see recursiveServices.
+// Patterns span all services directories, unless prefixed with a label, see rootsOf.
 func (c *ctl) Services(pattern string, toLog bool) []string {
-	if len(pattern) < len(c.basedir) || pattern[:len(c.basedir)] != c.basedir {
-		pattern = path.Join(c.basedir, pattern)
+	if rest, predicates := stateSelector(pattern); len(predicates) > 0 {
+		if rest == "" {
+			rest = "*"
+		}
+		return c.stateFilter(c.Services(rest, toLog), predicates)
+	}
+	if strings.HasPrefix(pattern, "re:") {
+		return c.regexServices(pattern[len("re:"):], toLog)
+	}
+	if patterns := braceExpand(pattern); len(patterns) > 1 {
+		dirs := []string{}
+		for _, pattern := range patterns {
+			for _, dir := range c.Services(pattern, toLog) {
+				if !contains(dirs, dir) {
+					dirs = append(dirs, dir)
+				}
+			}
+		}
+		return dirs
 	}
-	files, err := filepath.Glob(pattern)
-	if err != nil {
-		log.Printf("error getting services list: %s\n", err)
+	if strings.Contains(pattern, "**") {
+		return c.recursiveServices(pattern, toLog)
 	}
-	if toLog {
-		logs, err := filepath.Glob(path.Join(pattern, "log"))
+	if strings.HasPrefix(pattern, "@") {
+		dirs, err := c.groupServices(pattern[1:], toLog, nil)
 		if err != nil {
-			log.Printf("error getting logs list: %s\n", err)
-		} else {
-			files = append(files, logs...)
-			sort.Strings(files)
+			log.Printf("error resolving group: %s\n", err)
+		}
+		return dirs
+	}
+	roots, pattern := c.rootsOf(pattern)
+	files := []string{}
+	for _, root := range roots {
+		pattern := path.Join(root.dir, pattern)
+		found, err := filepath.Glob(pattern)
+		if err != nil {
+			log.Printf("error getting services list: %s\n", err)
 		}
+		if toLog {
+			logs, err := filepath.Glob(path.Join(pattern, "log"))
+			if err != nil {
+				log.Printf("error getting logs list: %s\n", err)
+			} else {
+				found = append(found, logs...)
+				sort.Strings(found)
+			}
+		}
+		files = append(files, found...)
 	}
 
 	dirs := []string{}
@@ -254,15 +540,73 @@ func (c *ctl) Services(pattern string, toLog bool) []string {
 	return dirs
 }
 
-// Status Prints all statuses matching id and optionally their log process statuses.
-func (c *ctl) Status(id string, toLog bool) {
-	// TODO: normally (up|down) and stuff?
+// Statuses Returns all statuses matching id and optionally their log process statuses.
+func (c *ctl) Statuses(id string, toLog bool) []*status {
 	services := c.Services(id, toLog)
 	statuses := make([]*status, len(services))
 	for i, dir := range services {
-		status := newStatus(dir, c.serviceName(dir))
-		statuses[i] = status
+		statuses[i] = newStatus(dir, c.serviceName(dir))
+	}
+	return statuses
+}
+
+// StatusesOf Returns statuses of all services matching patterns, see selected.
+func (c *ctl) StatusesOf(patterns []string, toLog bool) []*status {
+	services := c.selected(patterns, toLog, false)
+	statuses := make([]*status, len(services))
+	for i, dir := range services {
+		statuses[i] = newStatus(dir, c.serviceName(dir))
+	}
+	return statuses
+}
+
+// PrintStatuses Prints statuses in specified format.
+//
+// Empty format means human readable table, see statusFormat for the others.
+func (c *ctl) PrintStatuses(statuses []*status, format string) {
+	for _, status := range statuses {
+		if status.Errored() {
+			c.fail()
+		}
+	}
+	if format != "" {
+		if err := statusFormat(c.stdout, format, statuses); err != nil {
+			c.fail()
+			c.println(err)
+		}
+		return
+	}
+
+	alignStatuses(statuses)
+	for _, status := range statuses {
+		c.println(c.colorize(status.themeKey(), status.String()))
+	}
+}
 
+// inlineLogs Moves statuses of log services into statuses of their
+// parent services, if these are among statuses as well.
+func inlineLogs(statuses []*status) []*status {
+	parents := map[string]*status{}
+	for _, status := range statuses {
+		parents[status.name] = status
+	}
+	inlined := []*status{}
+	for _, status := range statuses {
+		if parent, ok := parents[strings.TrimSuffix(status.name, "/log")]; ok && parent != status {
+			parent.log = status
+			continue
+		}
+		inlined = append(inlined, status)
+	}
+	return inlined
+}
+
+// alignStatuses Makes offsets uniform among statuses, so that they print as a table.
+func alignStatuses(statuses []*status) {
+	if len(statuses) == 0 {
+		return
+	}
+	for _, status := range statuses {
 		for i, offset := range status.Offsets {
 			if statuses[0].Offsets[i] < offset {
 				statuses[0].Offsets[i] = offset
@@ -271,10 +615,14 @@ func (c *ctl) Status(id string, toLog bool) {
 	}
 	for _, status := range statuses {
 		status.Offsets = statuses[0].Offsets
-		c.println(status)
 	}
 }
 
+// Status Prints all statuses matching id and optionally their log process statuses.
+func (c *ctl) Status(id string, toLog bool) {
+	c.PrintStatuses(c.Statuses(id, toLog), c.format)
+}
+
 // control Sends action byte to service.
 func (c *ctl) control(action []byte, service string) error {
 	f, err := os.OpenFile(
@@ -291,39 +639,108 @@ func (c *ctl) control(action []byte, service string) error {
 }
 
 // ctl Delegates a single action for single service.
-func (c *ctl) ctl(action []byte, service string, start uint64, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	status := newStatus(service, c.serviceName(service))
+// Returns whether the service reached desired state (or was not meant to be waited for).
+func (c *ctl) ctl(action []byte, service string, start uint64, opts *ctlOpts) bool {
+	name := c.serviceName(service)
+	status := newStatus(service, name)
 	if status.Errored() {
+		c.fail()
 		c.println(status)
-		return
+		return false
 	}
-	if status.CheckControl(action) {
+	steps, err := c.sequence(action, service)
+	if err != nil {
+		c.fail()
+		c.println(err)
+		return false
+	}
+	if steps != nil && status.sv.Pid != 0 {
+		if action, err = c.runSequence(action, steps, service, start, opts); err != nil {
+			c.fail()
+			c.println(err)
+			return false
+		}
+	} else if status.CheckControl(action) {
+		if opts.verbose {
+			c.printf("%s: sending '%s'\n", name, action)
+		}
 		if err := c.control(action, service); err != nil {
+			c.fail()
 			c.println(err)
-			return
+			return false
 		}
+	} else if opts.verbose {
+		c.printf("%s: already got '%c', not sending\n", name, action[0])
 	}
 
-	timeout := time.After(7 * time.Second)
-	tick := time.Tick(100 * time.Millisecond)
+	if opts.noWait {
+		c.println(newStatus(service, name))
+		return true
+	}
+	if opts.verbose {
+		c.printf("%s: waiting up to %s\n", name, opts.wait)
+	}
+	status, ok := c.waitFor(action, service, start, opts.wait)
+	if !ok {
+		c.fail()
+		c.printf("%s%s\n", timeoutPrefix(status), status)
+		return false
+	}
+	if status.Errored() {
+		c.fail()
+	}
+	c.println(status)
+	return !status.Errored()
+}
+
+// waitFor Polls service until it reaches the state desired by action, or timeout passes.
+// Returns the last status and whether the state was reached.
+func (c *ctl) waitFor(action []byte, service string, start uint64, timeout time.Duration) (*status, bool) {
+	name := c.serviceName(service)
+	deadline := time.After(timeout)
+	tick := time.NewTicker(100 * time.Millisecond)
+	defer tick.Stop()
 	for {
 		select {
-		case <-timeout:
-			c.printf("TIMEOUT: ")
-			c.Status(service, false)
-			return
-		case <-tick:
-			status := newStatus(service, c.serviceName(service))
+		case <-deadline:
+			status := newStatus(service, name)
+			status.checkScript(service, action, start)
+			return status, false
+		case <-tick.C:
+			status := newStatus(service, name)
+			status.checkScript(service, action, start)
 			if status.Check(action, start) {
-				c.println(status)
-				return
+				return status, true
 			}
 		}
 	}
 }
 
+// timeoutPrefix Returns the prefix for a status of service
+// that did not reach the desired state in time.
+func timeoutPrefix(status *status) string {
+	if status.Starting() {
+		return "CHECK FAILED: "
+	}
+	return "TIMEOUT: "
+}
+
+// ctlAll Delegates action to all services asynchronically and waits for them.
+// At most opts.parallel services are handled at once.
+// Returns services that did not reach desired state.
+func (c *ctl) ctlAll(action []byte, services []string, start uint64, opts *ctlOpts) []string {
+	var mu sync.Mutex
+	failed := []string{}
+	each(services, opts.parallel, func(service string) {
+		if !c.ctl(action, service, start, opts) {
+			mu.Lock()
+			failed = append(failed, service)
+			mu.Unlock()
+		}
+	})
+	return failed
+}
+
 // Ctl Handles command supplied by user.
 //
 // Depending on the command, it might just exit, print help or propagate
@@ -332,47 +749,117 @@ func (c *ctl) ctl(action []byte, service string, start uint64, wg *sync.WaitGrou
 // If more than one service was specified with the command,
 // actions are delegated asynchronically.
 func (c *ctl) Ctl(cmdStr string) bool {
-	c.line.AppendHistory(cmdStr)
+	if strings.TrimSpace(cmdStr) == "" {
+		return false
+	}
+	if c.line != nil {
+		c.line.AppendHistory(cmdStr)
+	}
+	return c.run(cmdStr)
+}
+
+// run Handles command, see Ctl, without recording it in history.
+func (c *ctl) run(cmdStr string) bool {
 	start := svNow()
-	params := strings.Split(strings.TrimSpace(cmdStr), " ")
+	params := cmdSplit(cmdStr)
+	if alias, ok := c.aliases[params[0]]; ok {
+		params = append(cmdSplit(alias), params[1:]...)
+	}
 
 	cmd := cmdMatch(params[0])
 	if ctlCmd, ok := cmd.(ctlCmd); ok {
 		return ctlCmd.Run(c, params)
 	}
 	if cmd == nil {
+		c.fail()
 		c.printf("%s: unable to find action\n", params[0])
 		return false
 	}
 	action := cmd.Action()
 
-	if len(params) == 1 {
-		params = append(params, "*")
+	opts, params, err := c.parseOpts(params[1:])
+	if err != nil {
+		c.fail()
+		c.println(err)
+		return false
 	}
-	var wg sync.WaitGroup
-	for _, param := range params[1:] {
-		if param == "" {
-			continue
+	services := c.findServices(params, opts)
+	if c.denied(services) {
+		return false
+	}
+
+	if string(action) == "u" || string(action) == "d" {
+		c.ctlDeps(action, services, start, opts)
+	} else {
+		c.ctlAll(action, services, start, opts)
+	}
+	return false
+}
+
+// denied Refuses to control services, if we are not allowed to control
+// any of them, explaining why. Returns whether it refused.
+func (c *ctl) denied(services []string) bool {
+	names := []string{}
+	for _, service := range services {
+		if runsvDenied(service) {
+			names = append(names, c.serviceName(service))
+		}
+	}
+	if len(names) == 0 {
+		return false
+	}
+	c.fail()
+	c.printf(
+		"permission denied: %s can only be controlled by the owner of supervise directory (usually root)\n",
+		strings.Join(names, ", "),
+	)
+	return true
+}
+
+// findServices Returns unique services matching patterns, see selected.
+// Reports patterns that do not match anything.
+// Refuses to return all services when no patterns were given and nothing
+// was selected, to not act on everything by accident.
+//
+// With opts.withLog, log services of the matching services are included,
+// with opts.logOnly, they replace the matching services.
+func (c *ctl) findServices(patterns []string, opts *ctlOpts) []string {
+	services := []string{}
+	add := func(service string) {
+		if !contains(services, service) {
+			services = append(services, service)
 		}
-		services := c.Services(param, false)
-		if len(services) == 0 {
-			c.printf("%s: unable to find service\n", param)
+	}
+	if exclusionsOnly(patterns) && len(c.selection) == 0 {
+		c.fail()
+		c.println("no services given: specify NAMES ('*' for all) or select some first")
+		return nil
+	}
+	for _, service := range c.selected(patterns, false, true) {
+		if !opts.withLog && !opts.logOnly || path.Base(service) == "log" {
+			add(service)
 			continue
 		}
-		wg.Add(len(services))
-		for _, service := range services {
-			go c.ctl(action, service, start, &wg)
+		logService := path.Join(service, "log")
+		if fi, err := os.Stat(logService); err == nil && fi.IsDir() {
+			if opts.withLog {
+				add(service)
+			}
+			add(logService)
+		} else if opts.withLog {
+			add(service)
+		} else {
+			c.fail()
+			c.printf("%s: no log service\n", c.serviceName(service))
 		}
 	}
-	wg.Wait()
-
-	return false
+	return services
 }
 
 // Run Performs one tick of a input prompt event loop.
 // If this function returns true, the outside loop should terminate.
 func (c *ctl) Run() bool {
-	cmd, err := c.line.Prompt("svctl> ")
+	cmd, err := c.line.Prompt(c.Prompt())
 	if err == io.EOF {
 		c.println()
 		return true
@@ -383,11 +870,45 @@ func (c *ctl) Run() bool {
 	return c.Ctl(cmd)
 }
 
-// main Creates svctl entry point, prints all processes statuses and launches event loop.
+// main Creates svctl entry point.
+//
+// If a command was given, either with -c or as arguments, executes it and exits
+// with non-zero status if any of the actions failed. Otherwise shows the initial
+// view (all processes statuses by default) and launches event loop.
 func main() {
-	ctl := newCtl(os.Stdout)
+	cmd := flag.String("c", "", "execute `command` and exit")
+	format := flag.String("format", "", "print statuses as json, csv or Go `template`")
+	srcdir := flag.String("srcdir", "", "`directory` with service definitions to enable (default \"/etc/sv\")")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-format format] [-srcdir directory] [-c command | command [args...]]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *cmd == "" && flag.NArg() > 0 {
+		*cmd = cmdJoin(flag.Args())
+	}
+	if *cmd == "" && *format != "" {
+		*cmd = "status"
+	}
+
+	ctl := newCtl(os.Stdout, *cmd == "")
 	defer ctl.Close()
-	ctl.Status("*", true)
+	ctl.format = *format
+	if *srcdir != "" {
+		ctl.set("srcdir", "-srcdir", func() { ctl.srcdir = *srcdir })
+	}
+
+	if *cmd != "" {
+		ctl.run(*cmd)
+		if ctl.Failed() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if strings.TrimSpace(ctl.view) != "" {
+		ctl.run(ctl.view)
+	}
 	for !ctl.Run() {
 	}
 }
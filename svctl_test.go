@@ -191,6 +191,17 @@ func (r *runitRunner) Assert(t *testing.T, cmd *cmdDef) {
 	}
 }
 
+// AddService Creates service with run script (and check script, if not empty).
+// runsvdir picks it up on its next scan.
+func (r *runitRunner) AddService(name, run, check string) {
+	dir := path.Join(r.basedir, name)
+	fatal(os.MkdirAll(dir, 0755))
+	if check != "" {
+		fatal(ioutil.WriteFile(path.Join(dir, "check"), []byte(check), 0755))
+	}
+	fatal(ioutil.WriteFile(path.Join(dir, "run"), []byte(run), 0755))
+}
+
 func (r *runitRunner) AssertError(t *testing.T, msg string) {
 	stdout := r.stdout.ReadString()
 	if stdout != msg {
@@ -211,6 +222,7 @@ func TestCmd(t *testing.T) {
 		line:    liner.NewLiner(),
 		basedir: runit.basedir,
 		stdout:  runit.stdout,
+		wait:    svWait,
 	}
 
 	// Tests for correct usage.
@@ -279,7 +291,7 @@ func TestCmd(t *testing.T) {
 			}
 		}
 	}
-	svctl.Ctl("u")
+	svctl.Ctl("u *")
 	assert()
 	svctl.Ctl("s")
 	assert()
@@ -300,6 +312,58 @@ func TestCmd(t *testing.T) {
 	svctl.Ctl("u w")
 	runit.AssertError(t, "w   ERROR   unable to open supervise/ok")
 
+	// Tests for escalation and rolling restarts.
+	// Services are added only now, not to disturb the implicit * tests.
+	sleeper := "#!/bin/sh\nexec sleep 1000\n"
+	runit.AddService("stubborn", "#!/usr/bin/env bash\ntrap '' TERM\nwhile true; do sleep 0.1; done\n", "")
+	runit.AddService("roll0", sleeper, "")
+	runit.AddService("roll1", sleeper, "")
+	runit.AddService("roll2", sleeper, "")
+	runit.AddService("never", sleeper, "#!/bin/sh\nexit 1\n")
+	time.Sleep(6 * time.Second)
+
+	svctl.Ctl("force-stop --grace 1 -w 3 stubborn")
+	runit.AssertError(t, "stubborn: sending TERM")
+	runit.AssertError(t, "stubborn: still not done after 1s, sending KILL")
+	if line := runit.stdout.ReadString(); !strings.HasPrefix(line, "stubborn") || strings.Fields(line)[1] != "STOPPED" || !strings.Contains(line, "(took 1.") {
+		t.Errorf("ERROR IN FORCE: `%s` is not a report of stop after KILL", line)
+	}
+
+	started := func(name string) time.Duration {
+		sv := newStatus(path.Join(runit.basedir, name), name).sv
+		return time.Duration(sv.Time)*time.Second + time.Duration(sv.Nano)
+	}
+	begin := time.Now()
+	svctl.Ctl("rolling-restart --batch 2 --pause 1s -w 3 roll0 roll1 roll2")
+	if took := time.Since(begin); took < time.Second {
+		t.Errorf("ERROR IN ROLLING: took `%s`, without pause", took)
+	}
+	for runit.stdout.Len() != 0 {
+		if fields := strings.Fields(runit.stdout.ReadString()); !strings.HasPrefix(fields[0], "roll") || fields[1] != "RUNNING" {
+			t.Errorf("ERROR IN ROLLING: `%v` is not running", fields)
+		}
+	}
+	if d := started("roll1") - started("roll0"); d < -500*time.Millisecond || d > 500*time.Millisecond {
+		t.Errorf("ERROR IN ROLLING: roll0 and roll1 restarted `%s` apart, in different batches", d)
+	}
+	if d := started("roll2") - started("roll0"); d < time.Second {
+		t.Errorf("ERROR IN ROLLING: roll2 restarted `%s` after roll0, in the same batch", d)
+	}
+
+	svctl.failed = false
+	svctl.Ctl("rolling-restart --batch 2 -w 2 roll0 never roll1 roll2")
+	lines := []string{runit.stdout.ReadString(), runit.stdout.ReadString()}
+	if !strings.HasPrefix(lines[0], "CHECK FAILED: never") {
+		lines[0], lines[1] = lines[1], lines[0]
+	}
+	if !strings.HasPrefix(lines[0], "CHECK FAILED: never") || !strings.HasPrefix(lines[1], "roll0") {
+		t.Errorf("ERROR IN ROLLING: `%q` is not a failed batch", lines)
+	}
+	runit.AssertError(t, "not restarted: roll1, roll2")
+	if !svctl.Failed() {
+		t.Errorf("ERROR IN ROLLING: failed batch did not fail")
+	}
+
 	svctl.line.Close()
 	runit.Close()
 }
@@ -308,7 +372,8 @@ func TestCompleter(t *testing.T) {
 	allCmds := []string{
 		"up ", "start ", "down ", "stop ", "r ", "restart ", "once ",
 		"pause ", "cont ", "hup ", "reload ", "alarm ", "interrupt ",
-		"quit ", "1 ", "2 ", "term ", "kill ", "status ", "help ", "exit ",
+		"quit ", "1 ", "2 ", "term ", "kill ", "status ", "top ", "log ", "logs ", "list ", "enable ",
+		"disable ", "rolling-restart ", "force-stop ", "force-restart ", "force-reload ", "signal ", "rotate ", "tree ", "deps ", "config ", "cd ", "pwd ", "bookmark ", "select ", "selection ", "help ", "exit ",
 	}
 	defs := []struct {
 		line string
@@ -331,6 +396,10 @@ func TestCompleter(t *testing.T) {
 		{"? st", 4, "? ", []string{"start ", "stop ", "status "}, ""},
 		{"? h term", 3, "? ", []string{"hup ", "help "}, " term"},
 		{"? st term", 3, "? ", []string{"start ", "stop ", "status "}, " term"},
+		{"up -", 4, "up ", []string{"-w ", "--no-wait ", "-v ", "--parallel ", "--with-log ", "--log-only "}, ""},
+		{"restart --n r0", 10, "restart ", []string{"--no-wait "}, " r0"},
+		{"log r0 -", 8, "log r0 ", []string{"-n ", "-f ", "-d "}, ""},
+		{"exit -", 6, "exit ", []string{}, ""},
 	}
 
 	dir := createRunitDir()
@@ -360,3 +429,125 @@ func TestCompleter(t *testing.T) {
 
 	os.RemoveAll(dir)
 }
+
+func TestFailed(t *testing.T) {
+	defs := []struct {
+		cmd    string
+		failed bool
+	}{
+		{"help", false},
+		{"help up", false},
+		{"n w", true},
+		{"u i", true},
+	}
+
+	dir := createRunitDir()
+
+	for _, def := range defs {
+		svctl := ctl{basedir: path.Join(dir, "testdata"), stdout: &stdout{}}
+		svctl.Ctl(def.cmd)
+		if svctl.Failed() != def.failed {
+			t.Errorf("ERROR IN FAILED: `%t` != `%t` for `%s`", svctl.Failed(), def.failed, def.cmd)
+		}
+	}
+
+	os.RemoveAll(dir)
+}
+
+func TestInlineLogs(t *testing.T) {
+	running := &svState{Time: svNow() - 300, Pid: 123, State: 1, NormallyUp: true}
+	stopped := &svState{Time: svNow() - 5, NormallyUp: true}
+	statuses := []*status{
+		{name: "a", sv: running, svStatus: "RUNNING", Offsets: []int{5, 17}},
+		{name: "a/log", sv: stopped, svStatus: "STOPPED", Offsets: []int{5, 7}},
+		{name: "b", sv: stopped, svStatus: "STOPPED", Offsets: []int{5, 7}},
+		{name: "c/log", sv: running, svStatus: "RUNNING", Offsets: []int{5, 17}},
+	}
+	inlined := inlineLogs(statuses)
+	if len(inlined) != 3 || inlined[0].log != statuses[1] || inlined[1].log != nil || inlined[2].name != "c/log" {
+		t.Fatalf("ERROR IN INLINE: `%v`", inlined)
+	}
+	expected := "a       RUNNING (pid 123)   300s; log: STOPPED 5s, normally up"
+	// Second might have passed in between.
+	line := strings.Replace(inlined[0].String(), "301s", "300s", 1)
+	if line = strings.Replace(line, "STOPPED 6s", "STOPPED 5s", 1); line != expected {
+		t.Errorf("ERROR IN INLINE: `%s` != `%s`", line, expected)
+	}
+	if key := inlined[0].themeKey(); key != "loglost" {
+		t.Errorf("ERROR IN INLINE: `%s` != `loglost`", key)
+	}
+	if key := inlined[1].themeKey(); key != "stopped" {
+		t.Errorf("ERROR IN INLINE: `%s` != `stopped`", key)
+	}
+}
+
+func TestFindServices(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"foo/log", "bar"} {
+		fatal(os.MkdirAll(path.Join(dir, name), 0755))
+	}
+
+	stdout := &stdout{}
+	svctl := ctl{basedir: dir, stdout: stdout}
+	defs := []struct {
+		patterns []string
+		opts     ctlOpts
+		services []string
+		output   []string
+	}{
+		{[]string{"*"}, ctlOpts{}, []string{"bar", "foo"}, nil},
+		{[]string{"foo/log"}, ctlOpts{}, []string{"foo/log"}, nil},
+		{[]string{"*"}, ctlOpts{withLog: true}, []string{"bar", "foo", "foo/log"}, nil},
+		{[]string{"foo", "foo/log"}, ctlOpts{withLog: true}, []string{"foo", "foo/log"}, nil},
+		{[]string{"*"}, ctlOpts{logOnly: true}, []string{"foo/log"}, []string{"bar: no log service"}},
+		{[]string{"foo/log"}, ctlOpts{logOnly: true}, []string{"foo/log"}, nil},
+	}
+	for _, def := range defs {
+		stdout.Clear()
+		services := svctl.findServices(def.patterns, &def.opts)
+		for i, service := range services {
+			services[i] = svctl.serviceName(service)
+		}
+		if !equal(services, def.services) {
+			t.Errorf("ERROR IN SERVICES: `%v` != `%v` for `%v` %+v", services, def.services, def.patterns, def.opts)
+		}
+		if len(stdout.value) != len(def.output) || len(def.output) > 0 && !equal(stdout.value, def.output) {
+			t.Errorf("ERROR IN OUTPUT: `%v` != `%v` for `%v` %+v", stdout.value, def.output, def.patterns, def.opts)
+		}
+	}
+
+	if _, _, err := svctl.parseOpts([]string{"--with-log", "--log-only"}); err == nil {
+		t.Errorf("ERROR IN OPTS: expected error for --with-log and --log-only")
+	}
+}
+
+func TestStatusCheckScript(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	fakeService(dir, "foo", fakeStatus(svNow()-100, 42, false, 'u', 1), false)
+	service := path.Join(dir, "foo")
+	fatal(os.WriteFile(path.Join(service, "check"), []byte("#!/bin/sh\ntouch checked\nexit 1\n"), 0755))
+
+	status := newStatus(service, "foo")
+	if status.svStatus != "RUNNING" || status.Starting() {
+		t.Errorf("ERROR IN STATUS: `%s`", status)
+	}
+	if _, err := os.Stat(path.Join(service, "checked")); err == nil {
+		t.Errorf("ERROR IN STATUS: check script was run")
+	}
+
+	svctl := &ctl{stdout: &stdout{}, basedir: dir}
+	if _, ok := svctl.waitFor([]byte("d"), service, 0, 150*time.Millisecond); ok {
+		t.Errorf("ERROR IN WAIT: running service is down")
+	}
+	if _, err := os.Stat(path.Join(service, "checked")); err == nil {
+		t.Errorf("ERROR IN WAIT: check script was run for down")
+	}
+	status, ok := svctl.waitFor([]byte("u"), service, 0, 150*time.Millisecond)
+	if ok || !status.Starting() || timeoutPrefix(status) != "CHECK FAILED: " {
+		t.Errorf("ERROR IN WAIT: `%s` passed failing check script", status)
+	}
+}
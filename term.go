@@ -0,0 +1,44 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import "os"
+
+// keyPress Returns channel that gets closed as soon as a key is pressed
+// (or stdin is closed), so that long running views can be interrupted.
+//
+// Terminal is switched to non-canonical mode if possible, so that it does not
+// wait for a newline. Returned function restores the original mode and must
+// be called once done.
+func keyPress() (<-chan struct{}, func()) {
+	restore, err := termRaw()
+	if err != nil {
+		restore = func() {}
+	}
+	pressed := make(chan struct{})
+	go func() {
+		b := make([]byte, 1)
+		os.Stdin.Read(b)
+		close(pressed)
+	}()
+	return pressed, restore
+}
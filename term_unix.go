@@ -0,0 +1,58 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build linux || darwin || freebsd || openbsd || netbsd
+
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// termRaw Switches terminal attached to stdin to non-canonical, no echo mode.
+// Returns function restoring the previous mode.
+func termRaw() (func(), error) {
+	var mode syscall.Termios
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL, uintptr(syscall.Stdin), termGetAttr, uintptr(unsafe.Pointer(&mode)),
+	)
+	if errno != 0 {
+		return nil, errno
+	}
+	orig := mode
+
+	mode.Lflag &^= syscall.ICANON | syscall.ECHO
+	mode.Cc[syscall.VMIN] = 1
+	mode.Cc[syscall.VTIME] = 0
+	_, _, errno = syscall.Syscall(
+		syscall.SYS_IOCTL, uintptr(syscall.Stdin), termSetAttr, uintptr(unsafe.Pointer(&mode)),
+	)
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		syscall.Syscall(
+			syscall.SYS_IOCTL, uintptr(syscall.Stdin), termSetAttr, uintptr(unsafe.Pointer(&orig)),
+		)
+	}, nil
+}
@@ -0,0 +1,102 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// topRow Holds the part of status that is compared between refreshes.
+type topRow struct {
+	status string
+	pid    uint
+}
+
+// Top Repeatedly prints statuses matching ids, until a key is pressed.
+//
+// Statuses are sorted by uptime, so that the most recently (re)started ones
+// come first, and these that changed state or pid since the previous
+// refresh are highlighted.
+func (c *ctl) Top(ids []string, interval time.Duration) {
+	pressed, restore := keyPress()
+	defer restore()
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	prev := map[string]topRow{}
+	for {
+		statuses := c.StatusesOf(ids, true)
+		if c.inlineLog {
+			statuses = inlineLogs(statuses)
+		}
+		topSort(statuses)
+		alignStatuses(statuses)
+
+		c.printf("\033[H\033[2J")
+		c.printf("every %s, press any key to exit\n\n", interval)
+		prev = c.printTop(statuses, prev)
+
+		select {
+		case <-pressed:
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// topSort Sorts statuses by uptime, the most recently (re)started first.
+// Errored ones come last.
+func topSort(statuses []*status) {
+	sort.SliceStable(statuses, func(i, j int) bool {
+		if statuses[i].Errored() != statuses[j].Errored() {
+			return !statuses[i].Errored()
+		}
+		if statuses[i].Errored() {
+			return false
+		}
+		if statuses[i].sv.Time != statuses[j].sv.Time {
+			return statuses[i].sv.Time > statuses[j].sv.Time
+		}
+		return statuses[i].sv.Nano > statuses[j].sv.Nano
+	})
+}
+
+// printTop Prints statuses, highlighting these that changed state or pid
+// since prev. Returns rows to compare the next refresh with.
+func (c *ctl) printTop(statuses []*status, prev map[string]topRow) map[string]topRow {
+	curr := make(map[string]topRow, len(statuses))
+	for _, status := range statuses {
+		row := topRow{status.svStatus, 0}
+		if !status.Errored() {
+			row.pid = status.sv.Pid
+		}
+		curr[status.name] = row
+		if old, ok := prev[status.name]; ok && old != row {
+			c.println(c.colorize("changed", status.String()))
+		} else {
+			c.println(c.colorize(status.themeKey(), status.String()))
+		}
+	}
+	return curr
+}
@@ -0,0 +1,88 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestTop(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	now := svNow()
+	fakeService(dir, "a", fakeStatus(now-100, 1, false, 0, 1), false)
+	fakeService(dir, "b", fakeStatus(now-10, 2, false, 0, 1), false)
+	fakeService(dir, "c", fakeStatus(now-50, 0, false, 0, 0), true)
+	fakeService(dir, "e", nil, false)
+
+	stdout := &stdout{}
+	svctl := &ctl{stdout: stdout, basedir: dir, colors: true, theme: map[string]string{"changed": "7"}}
+	defs := []struct {
+		statuses    map[string][]byte
+		order       []string
+		highlighted []string
+	}{
+		{nil, []string{"b", "c", "a", "e"}, []string{}},
+		{nil, []string{"b", "c", "a", "e"}, []string{}},
+		{
+			map[string][]byte{"a": fakeStatus(now-1, 3, false, 0, 1)},
+			[]string{"a", "b", "c", "e"}, []string{"a"},
+		},
+		{
+			map[string][]byte{"b": fakeStatus(now-10, 2, true, 0, 1), "c": fakeStatus(now-50, 0, false, 'u', 0)},
+			[]string{"a", "b", "c", "e"}, []string{"b"},
+		},
+		{
+			map[string][]byte{"c": fakeStatus(now, 4, false, 0, 1)},
+			[]string{"c", "a", "b", "e"}, []string{"c"},
+		},
+	}
+	prev := map[string]topRow{}
+	for i, def := range defs {
+		for name, status := range def.statuses {
+			fatal(os.WriteFile(path.Join(dir, name, "supervise/status"), status, 0644))
+		}
+		stdout.Clear()
+		statuses := svctl.StatusesOf(nil, false)
+		topSort(statuses)
+		prev = svctl.printTop(statuses, prev)
+
+		order, highlighted := []string{}, []string{}
+		for _, line := range stdout.value {
+			if strings.HasPrefix(line, "\033[7m") {
+				line = line[len("\033[7m"):]
+				highlighted = append(highlighted, strings.Fields(line)[0])
+			}
+			order = append(order, strings.Fields(line)[0])
+		}
+		if !equal(order, def.order) {
+			t.Errorf("ERROR IN ORDER: `%v` != `%v` for refresh %d", order, def.order, i)
+		}
+		if !equal(highlighted, def.highlighted) {
+			t.Errorf("ERROR IN HIGHLIGHT: `%v` != `%v` for refresh %d", highlighted, def.highlighted, i)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// treeMaxDepth Limits how deep service trees are walked.
+const treeMaxDepth = 16
+
+// treeNode Represents a directory in service tree, either a service,
+// or a plain directory grouping other services.
+type treeNode struct {
+	dir      string
+	service  bool
+	children []*treeNode
+}
+
+// isService Returns whether dir is a service directory, i.e. has run script
+// or is supervised already.
+func isService(dir string) bool {
+	for _, name := range []string{"run", "supervise"} {
+		if _, err := os.Stat(path.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// runsvdirRe Matches runsvdir invocation in run script, capturing its directory.
+var runsvdirRe = regexp.MustCompile(`\brunsvdir\s+(?:-P\s+)?([^\s-][^\s;&|]*)`)
+
+// nestedDir Returns directory watched by runsvdir, if service runs
+// a nested one, or an empty string otherwise.
+func nestedDir(service string) string {
+	run, err := os.ReadFile(path.Join(service, "run"))
+	if err != nil {
+		return ""
+	}
+	m := runsvdirRe.FindSubmatch(run)
+	if m == nil {
+		return ""
+	}
+	dir := string(m[1])
+	if !path.IsAbs(dir) {
+		dir = path.Join(service, dir)
+	}
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// Tree Returns services and directories containing them below dir.
+//
+// Plain directories are descended into, as are directories watched
+// by nested runsvdir instances, which become children of their services.
+func (c *ctl) Tree(dir string) []*treeNode {
+	return c.tree(dir, 0, map[string]bool{})
+}
+
+func (c *ctl) tree(dir string, depth int, visited map[string]bool) []*treeNode {
+	real, err := filepath.EvalSymlinks(dir)
+	if err != nil || visited[real] || depth > treeMaxDepth {
+		return nil
+	}
+	visited[real] = true
+	defer delete(visited, real)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	nodes := []*treeNode{}
+	for _, entry := range entries {
+		child := path.Join(dir, entry.Name())
+		if fi, err := os.Stat(child); err != nil || !fi.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		node := &treeNode{dir: child, service: isService(child)}
+		if !node.service {
+			node.children = c.tree(child, depth+1, visited)
+			if len(node.children) == 0 {
+				continue
+			}
+		} else if nested := nestedDir(child); nested != "" {
+			node.children = c.tree(nested, depth+1, visited)
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].dir < nodes[j].dir })
+	return nodes
+}
+
+// treeServices Returns all services in nodes and below them.
+func treeServices(nodes []*treeNode) []string {
+	services := []string{}
+	var walk func(nodes []*treeNode)
+	walk = func(nodes []*treeNode) {
+		for _, node := range nodes {
+			if node.service && !contains(services, node.dir) {
+				services = append(services, node.dir)
+			}
+			walk(node.children)
+		}
+	}
+	walk(nodes)
+	return services
+}
+
+// recursiveServices Returns paths to all services, at any depth,
+// with names matching pattern, in which `**` matches any number of directories.
+func (c *ctl) recursiveServices(pattern string, toLog bool) []string {
+	roots, pattern := c.rootsOf(pattern)
+	dirs := []string{}
+	for _, root := range roots {
+		for _, service := range treeServices(c.Tree(root.dir)) {
+			candidates := []string{service}
+			if toLog {
+				candidates = append(candidates, path.Join(service, "log"))
+			}
+			for _, dir := range candidates {
+				name, err := filepath.Rel(root.dir, dir)
+				if fi, serr := os.Stat(dir); err == nil && serr == nil && fi.IsDir() && recursiveMatch(pattern, name) {
+					dirs = append(dirs, dir)
+				}
+			}
+		}
+	}
+	return dirs
+}
+
+// recursiveMatch Returns whether name matches pattern, in which `**`
+// matches any number (including zero) of path segments.
+func recursiveMatch(pattern, name string) bool {
+	return segmentsMatch(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func segmentsMatch(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if segmentsMatch(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return segmentsMatch(pattern[1:], name[1:])
+}
+
+// treeCounts Counts services that are up and down (including errored ones) in nodes.
+func treeCounts(nodes []*treeNode, statuses map[string]*status) (int, int) {
+	up, down := 0, 0
+	for _, service := range treeServices(nodes) {
+		if status := statuses[service]; !status.Errored() && status.sv.Pid != 0 {
+			up++
+		} else {
+			down++
+		}
+	}
+	return up, down
+}
+
+// PrintTree Prints service tree below dir, along with statuses of services
+// and up/down counts of subtrees.
+// Services directories themselves are shown with their full paths.
+func (c *ctl) PrintTree(dir string) {
+	nodes := c.Tree(dir)
+	statuses := map[string]*status{}
+	for _, service := range treeServices(nodes) {
+		statuses[service] = newStatus(service, c.serviceName(service))
+		if statuses[service].Errored() {
+			c.fail()
+		}
+	}
+	name := c.serviceName(dir)
+	if _, rel, ok := c.rootName(dir); !ok || rel == "." {
+		name = dir
+	}
+	up, down := treeCounts(nodes, statuses)
+	c.printf("%s (%d up, %d down)\n", name, up, down)
+	c.printTree(nodes, statuses, "")
+}
+
+func (c *ctl) printTree(nodes []*treeNode, statuses map[string]*status, indent string) {
+	for i, node := range nodes {
+		branch, next := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, next = "└── ", "    "
+		}
+		line := path.Base(node.dir)
+		if node.service {
+			status := statuses[node.dir]
+			line = fmt.Sprintf("%s   %s", line, status.short())
+			if len(node.children) > 0 {
+				up, down := treeCounts(node.children, statuses)
+				line = fmt.Sprintf("%s (nested: %d up, %d down)", line, up, down)
+			}
+			line = c.colorize(status.themeKey(), line)
+		} else {
+			up, down := treeCounts(node.children, statuses)
+			line = fmt.Sprintf("%s/ (%d up, %d down)", line, up, down)
+		}
+		c.printf("%s%s%s\n", indent, branch, line)
+		c.printTree(node.children, statuses, indent+next)
+	}
+}
@@ -0,0 +1,141 @@
+// svctl
+// Copyright (C) 2026 Karol 'Kenji Takahashi' Woźniak
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the "Software"),
+// to deal in the Software without restriction, including without limitation
+// the rights to use, copy, modify, merge, publish, distribute, sublicense,
+// and/or sell copies of the Software, and to permit persons to whom the
+// Software is furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES
+// OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM,
+// DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT,
+// TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE
+// OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	dir, err := os.MkdirTemp("", "svctl_tests")
+	fatal(err)
+	defer os.RemoveAll(dir)
+	up := fakeStatus(svNow()-100, 42, false, 'u', 1)
+	down := fakeStatus(svNow()-100, 0, false, 'd', 0)
+	fakeService(dir, "web", up, false)
+	fakeService(dir, "tenants/acme/api", up, false)
+	fakeService(dir, "tenants/acme/db", down, true)
+	fakeService(dir, "nested", up, false)
+	fakeService(dir, "nested/sv/worker", up, false)
+	fatal(os.MkdirAll(path.Join(dir, "empty/dir"), 0755))
+	fatal(os.WriteFile(path.Join(dir, "nested/run"), []byte("#!/bin/sh\nexec runsvdir -P ./sv\n"), 0755))
+
+	if nested := nestedDir(path.Join(dir, "nested")); nested != path.Join(dir, "nested/sv") {
+		t.Errorf("ERROR IN NESTED: `%s`", nested)
+	}
+	if nested := nestedDir(path.Join(dir, "web")); nested != "" {
+		t.Errorf("ERROR IN NESTED: `%s`", nested)
+	}
+
+	stdout := &stdout{}
+	svctl := ctl{basedir: dir, stdout: stdout}
+	svctl.Ctl("tree")
+	expected := []string{
+		dir + " (4 up, 1 down)",
+		"├── nested   RUNNING (pid 42) Ns (nested: 1 up, 0 down)",
+		"│   └── worker   RUNNING (pid 42) Ns",
+		"├── tenants/ (1 up, 1 down)",
+		"│   └── acme/ (1 up, 1 down)",
+		"│       ├── api   RUNNING (pid 42) Ns",
+		"│       └── db   STOPPED Ns",
+		"└── web   RUNNING (pid 42) Ns",
+	}
+	uptime := regexp.MustCompile(`\d+s`)
+	for i, line := range stdout.value {
+		stdout.value[i] = uptime.ReplaceAllString(line, "Ns")
+	}
+	if !equal(stdout.value, expected) {
+		t.Errorf("ERROR IN TREE: `%q` != `%q`", stdout.value, expected)
+	}
+	if svctl.Failed() {
+		t.Errorf("ERROR IN TREE: unexpected failure")
+	}
+
+	fatal(os.MkdirAll(path.Join(dir, "tenants/acme/broken"), 0755))
+	fatal(os.WriteFile(path.Join(dir, "tenants/acme/broken/run"), nil, 0755))
+	stdout.Clear()
+	svctl.Ctl("tree tenants")
+	expected = []string{
+		"tenants (1 up, 2 down)",
+		"└── acme/ (1 up, 2 down)",
+		"    ├── api   RUNNING (pid 42) Ns",
+		"    ├── broken   ERROR unable to open supervise/ok",
+		"    └── db   STOPPED Ns",
+	}
+	for i, line := range stdout.value {
+		stdout.value[i] = uptime.ReplaceAllString(line, "Ns")
+	}
+	if !equal(stdout.value, expected) {
+		t.Errorf("ERROR IN TREE: `%q` != `%q`", stdout.value, expected)
+	}
+	if !svctl.Failed() {
+		t.Errorf("ERROR IN TREE: expected failure for errored service")
+	}
+	fatal(os.RemoveAll(path.Join(dir, "tenants/acme/broken")))
+
+	defs := []struct {
+		pattern  string
+		services []string
+	}{
+		{"**", []string{"nested", "nested/sv/worker", "tenants/acme/api", "tenants/acme/db", "web"}},
+		{"tenants/**", []string{"tenants/acme/api", "tenants/acme/db"}},
+		{"**/d*", []string{"tenants/acme/db"}},
+		{"**/worker@up", []string{"nested/sv/worker"}},
+		{"re:worker$", []string{"nested/sv/worker"}},
+		{"re:^tenants/.*/d", []string{"tenants/acme/db"}},
+	}
+	for _, def := range defs {
+		services := svctl.Services(def.pattern, false)
+		names := make([]string, len(services))
+		for i, service := range services {
+			names[i] = svctl.serviceName(service)
+		}
+		if !equal(names, def.services) {
+			t.Errorf("ERROR IN RECURSIVE: `%v` != `%v` for `%s`", names, def.services, def.pattern)
+		}
+	}
+}
+
+func TestRecursiveMatch(t *testing.T) {
+	defs := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"**", "a", true},
+		{"**", "a/b/c", true},
+		{"a/**", "a/b/c", true},
+		{"a/**/c", "a/c", true},
+		{"a/**/c", "a/b/x/c", true},
+		{"a/**/c", "a/b/x", false},
+		{"**/b*", "a/x/bar", true},
+		{"a/*", "a/b/c", false},
+	}
+	for _, def := range defs {
+		if match := recursiveMatch(def.pattern, def.name); match != def.match {
+			t.Errorf("ERROR IN MATCH: `%t` != `%t` for `%s` `%s`", match, def.match, def.pattern, def.name)
+		}
+	}
+}